@@ -70,7 +70,7 @@ func RequestERC20Transfers(p *ERC20TransfersParam, client *rpc.Client) ([]ERC20T
 	}
 
 	logParam := rpc.CreateNewFilterParams(p.Address, p.FromBlock, p.ToBlock, ftb.Build())
-	logs, err := client.Eth.GetLogs(logParam)
+	logs, err := client.Eth.GetLogsPaged(logParam, nil)
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,306 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+// DefaultMaxBatchSize is the chunk size of a BatchBuilder. Many providers reject batches with more than 100 requests.
+const DefaultMaxBatchSize = 100
+
+// BatchBuilder collects typed requests and sends them as one or more batch requests.
+//
+// Every queued request returns a future which is resolved by Execute():
+//
+//	batch := client.NewBatch()
+//	block := batch.GetBlockByNumber(4000000, true)
+//	balance := batch.GetBalance(address, rpctypes.QuantityLatest())
+//	err := batch.Execute()
+//	b, err := block.Get()
+//
+// Errors are reported per request: an rpc error or a malformed result only fails the future of that request.
+// If a whole chunk fails (e.g. connection refused), Execute returns the error and all futures of the chunk fail with it.
+type BatchBuilder struct {
+	client  *Client
+	maxSize int
+	items   []*batchFuture
+}
+
+type batchFuture struct {
+	request  *RPCRequest
+	decode   func(result interface{}) (interface{}, error) // nil for raw responses
+	executed bool
+	value    interface{}
+	err      error
+}
+
+// NewBatch returns an empty BatchBuilder which sends chunks of at most DefaultMaxBatchSize requests.
+func (client *Client) NewBatch() *BatchBuilder {
+	return &BatchBuilder{
+		client:  client,
+		maxSize: DefaultMaxBatchSize,
+		items:   make([]*batchFuture, 0),
+	}
+}
+
+// SetMaxSize sets the maximum number of requests per batch request. Values below 1 send all requests in one batch.
+func (batch *BatchBuilder) SetMaxSize(maxSize int) *BatchBuilder {
+	batch.maxSize = maxSize
+	return batch
+}
+
+// Len returns the number of queued requests.
+func (batch *BatchBuilder) Len() int {
+	return len(batch.items)
+}
+
+// Execute sends all queued requests and resolves their futures.
+func (batch *BatchBuilder) Execute() error {
+	return batch.ExecuteContext(context.Background())
+}
+
+// ExecuteContext is like Execute but takes a context.Context for deadlines and cancellation.
+func (batch *BatchBuilder) ExecuteContext(ctx context.Context) error {
+	size := batch.maxSize
+	if size < 1 {
+		size = len(batch.items)
+	}
+
+	var firstErr error
+
+	for start := 0; start < len(batch.items); start += size {
+		end := start + size
+		if end > len(batch.items) {
+			end = len(batch.items)
+		}
+
+		if err := batch.executeChunk(ctx, batch.items[start:end]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func (batch *BatchBuilder) executeChunk(ctx context.Context, items []*batchFuture) error {
+	requests := make([]interface{}, len(items))
+	for k, item := range items {
+		requests[k] = item.request
+	}
+
+	responses, err := batch.client.batch(ctx, requests)
+	if err != nil {
+		err = contextError(ctx, err)
+		for _, item := range items {
+			item.resolve(nil, err)
+		}
+		return err
+	}
+
+	byID := make(map[uint]*RPCResponse, len(responses))
+	for k := range responses {
+		byID[responses[k].ID] = &responses[k]
+	}
+
+	for _, item := range items {
+		response, ok := byID[item.request.ID]
+
+		switch {
+		case !ok:
+			item.resolve(nil, fmt.Errorf("no response for request %v with id %d", item.request.Method, item.request.ID))
+		case response.Error != nil:
+			item.resolve(nil, response.Error)
+		case item.decode == nil:
+			item.resolve(response, nil)
+		case response.Result == nil:
+			item.resolve(nil, fmt.Errorf("m: %v, p: %v didn't return error but also no response", item.request.Method, item.request.Params))
+		default:
+			item.resolve(item.decode(response.Result))
+		}
+	}
+
+	return nil
+}
+
+// add queues a request. The ids are unique within the builder, independent of the client's id settings.
+func (batch *BatchBuilder) add(decode func(result interface{}) (interface{}, error), method string, params ...interface{}) *batchFuture {
+	item := &batchFuture{
+		request: &RPCRequest{
+			ID:      uint(len(batch.items)),
+			JSONRPC: "2.0",
+			Method:  method,
+			Params:  params,
+		},
+		decode: decode,
+	}
+
+	batch.items = append(batch.items, item)
+
+	return item
+}
+
+func (item *batchFuture) resolve(value interface{}, err error) {
+	item.executed = true
+	item.value = value
+	item.err = err
+}
+
+func (item *batchFuture) get() (interface{}, error) {
+	if !item.executed {
+		return nil, BatchNotExecuted
+	}
+
+	return item.value, item.err
+}
+
+// ResponseFuture is the raw response of a request queued with Call.
+type ResponseFuture struct{ item *batchFuture }
+
+func (future ResponseFuture) Get() (*RPCResponse, error) {
+	value, err := future.item.get()
+	if err != nil {
+		return nil, err
+	}
+	return value.(*RPCResponse), nil
+}
+
+// BlockFuture resolves to the block of GetBlockByNumber or GetBlockByHash.
+type BlockFuture struct{ item *batchFuture }
+
+func (future BlockFuture) Get() (*rpctypes.EtherBlock, error) {
+	value, err := future.item.get()
+	if err != nil {
+		return nil, err
+	}
+	return value.(*rpctypes.EtherBlock), nil
+}
+
+// TransactionFuture resolves to the transaction of GetTransactionByHash.
+type TransactionFuture struct{ item *batchFuture }
+
+func (future TransactionFuture) Get() (*rpctypes.EtherTransaction, error) {
+	value, err := future.item.get()
+	if err != nil {
+		return nil, err
+	}
+	return value.(*rpctypes.EtherTransaction), nil
+}
+
+// ReceiptFuture resolves to the receipt of GetTransactionReceipt.
+type ReceiptFuture struct{ item *batchFuture }
+
+func (future ReceiptFuture) Get() (*rpctypes.EtherTransactionReceipt, error) {
+	value, err := future.item.get()
+	if err != nil {
+		return nil, err
+	}
+	return value.(*rpctypes.EtherTransactionReceipt), nil
+}
+
+// EtherValueFuture resolves to the value of GetBalance or GasPrice.
+type EtherValueFuture struct{ item *batchFuture }
+
+func (future EtherValueFuture) Get() (*rpctypes.EtherValue, error) {
+	value, err := future.item.get()
+	if err != nil {
+		return nil, err
+	}
+	return value.(*rpctypes.EtherValue), nil
+}
+
+// Int64Future resolves to the number of BlockNumber or GetTransactionCount.
+type Int64Future struct{ item *batchFuture }
+
+func (future Int64Future) Get() (int64, error) {
+	value, err := future.item.get()
+	if err != nil {
+		return -1, err
+	}
+	return value.(int64), nil
+}
+
+/*
+	queued requests
+ */
+
+// Call queues an arbitrary request, its future resolves to the raw response. Unlike the typed requests a null result is no error.
+func (batch *BatchBuilder) Call(method string, params ...interface{}) ResponseFuture {
+	return ResponseFuture{item: batch.add(nil, method, params...)}
+}
+
+func (batch *BatchBuilder) BlockNumber() Int64Future {
+	return Int64Future{item: batch.add(decodeInt64, MethodEthBlockNumber)}
+}
+
+func (batch *BatchBuilder) GasPrice() EtherValueFuture {
+	return EtherValueFuture{item: batch.add(decodeEtherValue, MethodGasPrice)}
+}
+
+func (batch *BatchBuilder) GetBalance(address string, quantity *rpctypes.Quantity) EtherValueFuture {
+	return EtherValueFuture{item: batch.add(decodeEtherValue, MethodGetBalance, address, quantity.HexStringOrTag())}
+}
+
+func (batch *BatchBuilder) GetTransactionCount(address string, quantity *rpctypes.Quantity) Int64Future {
+	return Int64Future{item: batch.add(decodeInt64, MethodGetTransactionCount, address, quantity.HexStringOrTag())}
+}
+
+func (batch *BatchBuilder) GetBlockByNumber(blockNumber int64, full bool) BlockFuture {
+	return BlockFuture{item: batch.add(decodeBlock(full), MethodGetBlockByNumber, new(rpctypes.HexString).FromInt64(blockNumber).String(), full)}
+}
+
+func (batch *BatchBuilder) GetBlockByHash(hash string, full bool) BlockFuture {
+	return BlockFuture{item: batch.add(decodeBlock(full), MethodGetBlockByHash, hash, full)}
+}
+
+func (batch *BatchBuilder) GetTransactionByHash(hash string) TransactionFuture {
+	return TransactionFuture{item: batch.add(func(result interface{}) (interface{}, error) {
+		return getTransactionFromResponse(result)
+	}, MethodGetTransactionByHash, hash)}
+}
+
+func (batch *BatchBuilder) GetTransactionReceipt(hash string) ReceiptFuture {
+	return ReceiptFuture{item: batch.add(func(result interface{}) (interface{}, error) {
+		js, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		return new(rpctypes.TransactionReceiptRaw).FromJSON(js)
+	}, MethodGetTransactionReceipt, hash)}
+}
+
+func decodeBlock(full bool) func(result interface{}) (interface{}, error) {
+	return func(result interface{}) (interface{}, error) {
+		return getBlockFromResponse(result, full)
+	}
+}
+
+func decodeEtherValue(result interface{}) (interface{}, error) {
+	val, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("could not parse string from %v", result)
+	}
+
+	r, err := rpctypes.HexToBigInt(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(rpctypes.EtherValue).FromBigInt(r), nil
+}
+
+func decodeInt64(result interface{}) (interface{}, error) {
+	val, ok := result.(string)
+	if !ok {
+		return nil, fmt.Errorf("could not parse string from %v", result)
+	}
+
+	hs, err := rpctypes.NewHexString(val)
+	if err != nil {
+		return nil, err
+	}
+
+	return hs.Int64(), nil
+}
@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+// batchTestServer answers eth_blockNumber, eth_getBalance and eth_getTransactionReceipt (with an error)
+// and counts the batches.
+func batchTestServer() (*httptest.Server, *int32) {
+	return testServer(func(request RPCRequest) RPCResponse {
+		switch request.Method {
+		case MethodEthBlockNumber:
+			return RPCResponse{Result: "0x10"}
+		case MethodGetBalance:
+			return RPCResponse{Result: "0xde0b6b3a7640000"}
+		case MethodGetTransactionReceipt:
+			return RPCResponse{Error: &RPCError{Code: -32000, Message: "unknown transaction"}}
+		}
+		return RPCResponse{}
+	})
+}
+
+func TestBatchBuilder_Execute(t *testing.T) {
+	server, batches := batchTestServer()
+	defer server.Close()
+
+	batch := NewRPCClient(server.URL).NewBatch().SetMaxSize(2)
+
+	blockNumber := batch.BlockNumber()
+	balance := batch.GetBalance("0x0000000000000000000000000000000000000001", rpctypes.QuantityLatest())
+	receipt := batch.GetTransactionReceipt("0x01")
+	raw := batch.Call(MethodGetTransactionByHash, "0x01")
+	secondBlockNumber := batch.BlockNumber()
+
+	if _, err := blockNumber.Get(); err != BatchNotExecuted {
+		t.Errorf("wrong error before execution [Expected: %v, Actual: %v]", BatchNotExecuted, err)
+	}
+
+	if err := batch.Execute(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if *batches != 3 {
+		t.Errorf("wrong number of chunks [Expected: %v, Actual: %v]", 3, *batches)
+	}
+
+	if n, err := blockNumber.Get(); err != nil || n != 16 {
+		t.Errorf("wrong block number [Expected: %v, Actual: %v, %v]", 16, n, err)
+	}
+
+	if n, err := secondBlockNumber.Get(); err != nil || n != 16 {
+		t.Errorf("wrong block number [Expected: %v, Actual: %v, %v]", 16, n, err)
+	}
+
+	if value, err := balance.Get(); err != nil || value.BigInt().String() != "1000000000000000000" {
+		t.Errorf("wrong balance [Expected: %v, Actual: %v, %v]", "1000000000000000000", value, err)
+	}
+
+	if _, err := receipt.Get(); err == nil || err.(*RPCError).Code != -32000 {
+		t.Errorf("wrong receipt error [Expected: %v, Actual: %v]", -32000, err)
+	}
+
+	if response, err := raw.Get(); err != nil || response.Result != nil {
+		t.Errorf("wrong raw response [Expected: %v, Actual: %v, %v]", nil, response, err)
+	}
+}
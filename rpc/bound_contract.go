@@ -0,0 +1,231 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/Leondroids/go-ethereum-rpc/rpcutils"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BoundContract calls, transacts with and reads the logs of the contract at Address using its ABI.
+type BoundContract struct {
+	Address rpctypes.EtherAddress
+	ABI     *rpcutils.ABI
+	From    string        // (optional) the sender of calls
+	Nonces  *NonceManager // assigns the nonces of Transact
+	client  *Client
+}
+
+// ContractEvent is a log of the contract decoded with its ABI.
+type ContractEvent struct {
+	Name   string
+	Values []interface{}          // the inputs of the event in their order
+	Args   map[string]interface{} // the inputs of the event by name, unnamed inputs are called arg0, arg1, ...
+	Log    rpctypes.EtherLog
+}
+
+// NewBoundContract binds abi to the contract at address. Transactions get their nonces from a new NonceManager,
+// share one between contracts sending from the same account.
+func NewBoundContract(client *Client, address string, abi *rpcutils.ABI) (*BoundContract, error) {
+	if abi == nil {
+		return nil, errors.New("abi cannot be nil")
+	}
+
+	ea, err := new(rpctypes.EtherAddress).FromString(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoundContract{
+		Address: *ea,
+		ABI:     abi,
+		Nonces:  NewNonceManager(client),
+		client:  client,
+	}, nil
+}
+
+// Call executes the constant method with eth_call on the latest block and returns its decoded return values.
+// If the call reverts with a custom error of the ABI, the Reason of the *RevertError is the decoded error, e.g. "InsufficientBalance(1, 2)".
+func (c *BoundContract) Call(method string, args ...interface{}) ([]interface{}, error) {
+	return c.CallContext(context.Background(), method, args...)
+}
+
+// CallContext is like Call but takes a context.Context for deadlines and cancellation.
+func (c *BoundContract) CallContext(ctx context.Context, method string, args ...interface{}) ([]interface{}, error) {
+	m, err := c.ABI.Method(method)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := m.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &EthCallParams{
+		From: c.From,
+		To:   c.Address.String(),
+		Data: rpctypes.NewHexStringFromBytes(data).Hash(),
+	}
+
+	result, err := c.client.Eth.CallAtContext(ctx, params, BlockByQuantity(rpctypes.QuantityLatest()), nil)
+	if err != nil {
+		return nil, c.decodeRevert(err)
+	}
+
+	return m.Unpack(result.Bytes())
+}
+
+// decodeRevert sets the reason of a revert with a custom error of the ABI.
+func (c *BoundContract) decodeRevert(err error) error {
+	revert, ok := err.(*RevertError)
+	if !ok || revert.Reason != "" {
+		return err
+	}
+
+	abiError, e := c.ABI.ErrorBySelector(revert.Data.Bytes())
+	if e != nil {
+		return err
+	}
+
+	values, e := abiError.Unpack(revert.Data.Bytes())
+	if e != nil {
+		return err
+	}
+
+	args := make([]string, len(values))
+	for k, v := range values {
+		args[k] = fmt.Sprint(v)
+	}
+	revert.Reason = abiError.Name + "(" + strings.Join(args, ", ") + ")"
+
+	return revert
+}
+
+// Transact sends a transaction calling method, signed with key. tx supplies value, gas and prices
+// (see NonceManager.Send), its To, Data and Nonce are set by Transact. If tx.Gas is 0, it is estimated.
+func (c *BoundContract) Transact(tx *RawTransaction, key *ecdsa.PrivateKey, method string, args ...interface{}) (*SignedTransaction, error) {
+	return c.TransactContext(context.Background(), tx, key, method, args...)
+}
+
+// TransactContext is like Transact but takes a context.Context for deadlines and cancellation.
+func (c *BoundContract) TransactContext(ctx context.Context, tx *RawTransaction, key *ecdsa.PrivateKey, method string, args ...interface{}) (*SignedTransaction, error) {
+	if tx == nil {
+		return nil, errors.New("transaction cannot be nil")
+	}
+	if key == nil {
+		return nil, errors.New("private key cannot be nil")
+	}
+
+	m, err := c.ABI.Method(method)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.Value != nil && tx.Value.Sign() > 0 && !m.IsPayable() {
+		return nil, fmt.Errorf("method %v is not payable", m.Signature())
+	}
+
+	data, err := m.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	address := c.Address
+	tx.To = &address
+	tx.Data = data
+
+	if tx.Gas == 0 {
+		gas, err := c.client.Eth.EstimateGasContext(ctx, &EthEstimateGasParams{
+			From:  strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex()),
+			To:    address.String(),
+			Value: tx.Value,
+			Data:  rpctypes.NewHexStringFromBytes(data).Hash(),
+		})
+		if err != nil {
+			return nil, c.decodeRevert(err)
+		}
+		tx.Gas = gas.Uint64()
+	}
+
+	return c.Nonces.SendContext(ctx, tx, key)
+}
+
+// FilterLogs returns the decoded logs of the event between from and to (nil means latest). query holds the accepted
+// values of the indexed inputs in their order, nil matches any value. Large block ranges are paged, see GetLogsPaged.
+func (c *BoundContract) FilterLogs(event string, from, to *rpctypes.Quantity, query ...[]interface{}) ([]ContractEvent, error) {
+	return c.FilterLogsContext(context.Background(), event, from, to, query...)
+}
+
+// FilterLogsContext is like FilterLogs but takes a context.Context for deadlines and cancellation.
+func (c *BoundContract) FilterLogsContext(ctx context.Context, event string, from, to *rpctypes.Quantity, query ...[]interface{}) ([]ContractEvent, error) {
+	e, err := c.ABI.Event(event)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := e.Topics(query...)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &NewFilterParams{
+		FromBlock: from,
+		ToBlock:   to,
+		Addresses: []string{c.Address.String()},
+		Topics:    topics,
+	}
+
+	logs, err := c.client.Eth.GetLogsPagedContext(ctx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]ContractEvent, len(logs))
+	for k := range logs {
+		decoded, err := c.decodeLog(e, &logs[k])
+		if err != nil {
+			return nil, err
+		}
+		events[k] = *decoded
+	}
+
+	return events, nil
+}
+
+// ParseLog decodes a log of the contract with the event of the ABI whose ID is the first topic of the log.
+func (c *BoundContract) ParseLog(log *rpctypes.EtherLog) (*ContractEvent, error) {
+	if log == nil || len(log.Topics) == 0 {
+		return nil, errors.New("log without topics")
+	}
+
+	e, err := c.ABI.EventByID(log.Topics[0].Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return c.decodeLog(e, log)
+}
+
+func (c *BoundContract) decodeLog(e *rpcutils.ABIEvent, log *rpctypes.EtherLog) (*ContractEvent, error) {
+	values, err := e.DecodeLog(log)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make(map[string]interface{}, len(values))
+	for k, input := range e.Inputs {
+		name := input.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%v", k)
+		}
+		args[name] = values[k]
+	}
+
+	return &ContractEvent{Name: e.Name, Values: values, Args: args, Log: *log}, nil
+}
@@ -0,0 +1,174 @@
+package rpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/Leondroids/go-ethereum-rpc/rpcutils"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const boundContractABI = `[
+	{"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}],"anonymous":false},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}
+]`
+
+const (
+	boundContractAddress = "0x7f0d15c7faae65896648c8273b6d7e43f58fa842"
+	boundContractHolder  = "0x0000000000000000000000000000000000000001"
+	transferLogJSON      = `{"address":"0x7f0d15c7faae65896648c8273b6d7e43f58fa842","blockHash":"0x01","blockNumber":"0x10","data":"0x00000000000000000000000000000000000000000000000000000000000003e8","logIndex":"0x0","removed":false,"topics":["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef","0x0000000000000000000000000000000000000000000000000000000000000001","0x0000000000000000000000000000000000000000000000000000000000000002"],"transactionHash":"0x01","transactionIndex":"0x0"}`
+)
+
+func testBoundContract(t *testing.T, handle func(request RPCRequest) RPCResponse) (*BoundContract, func()) {
+	server, _ := testServer(handle)
+
+	abi, err := rpcutils.ParseABI([]byte(boundContractABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contract, err := NewBoundContract(NewRPCClient(server.URL), boundContractAddress, abi)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return contract, server.Close
+}
+
+func TestBoundContract_Call(t *testing.T) {
+	var call map[string]interface{}
+	contract, closeServer := testBoundContract(t, func(request RPCRequest) RPCResponse {
+		call = request.Params.([]interface{})[0].(map[string]interface{})
+		return RPCResponse{Result: "0x00000000000000000000000000000000000000000000000000000000000003e8"}
+	})
+	defer closeServer()
+
+	values, err := contract.Call("balanceOf", boundContractHolder)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if values[0].(*big.Int).Int64() != 1000 {
+		t.Errorf("wrong balance [Expected: %v, Actual: %v]", 1000, values[0])
+	}
+
+	expected := "0x70a082310000000000000000000000000000000000000000000000000000000000000001"
+	if call["to"] != boundContractAddress || call["data"] != expected {
+		t.Errorf("wrong call [Expected: %v %v, Actual: %v %v]", boundContractAddress, expected, call["to"], call["data"])
+	}
+}
+
+func TestBoundContract_CallCustomError(t *testing.T) {
+	data := "0xcf479181" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002"
+
+	contract, closeServer := testBoundContract(t, func(request RPCRequest) RPCResponse {
+		return RPCResponse{Error: &RPCError{Code: 3, Message: "execution reverted", Data: data}}
+	})
+	defer closeServer()
+
+	_, err := contract.Call("balanceOf", boundContractHolder)
+
+	revert, ok := err.(*RevertError)
+	if !ok || revert.Reason != "InsufficientBalance(1, 2)" {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", "execution reverted: InsufficientBalance(1, 2)", err)
+	}
+}
+
+func TestBoundContract_FilterLogs(t *testing.T) {
+	var filter map[string]interface{}
+	contract, closeServer := testBoundContract(t, func(request RPCRequest) RPCResponse {
+		filter = request.Params.([]interface{})[0].(map[string]interface{})
+		var logs interface{}
+		json.Unmarshal([]byte("["+transferLogJSON+"]"), &logs)
+		return RPCResponse{Result: logs}
+	})
+	defer closeServer()
+
+	events, err := contract.FilterLogs("Transfer", rpctypes.QuantityBlock(16), rpctypes.QuantityBlock(16), nil, []interface{}{"0x0000000000000000000000000000000000000002"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	js, _ := json.Marshal(filter["topics"])
+	expected := `["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",null,"0x0000000000000000000000000000000000000000000000000000000000000002"]`
+	if string(js) != expected {
+		t.Errorf("wrong topics [Expected: %v, Actual: %v]", expected, string(js))
+	}
+
+	if len(events) != 1 || events[0].Name != "Transfer" || events[0].Args["value"].(*big.Int).Int64() != 1000 || events[0].Args["to"].(*rpctypes.EtherAddress).String() != "0x0000000000000000000000000000000000000002" {
+		t.Errorf("wrong events [Expected: %v, Actual: %v]", "Transfer of 1000 to 0x...02", events)
+	}
+}
+
+func TestBoundContract_ParseLog(t *testing.T) {
+	contract, closeServer := testBoundContract(t, func(request RPCRequest) RPCResponse { return RPCResponse{} })
+	defer closeServer()
+
+	log, err := new(rpctypes.EtherLogRaw).FromJSON([]byte(transferLogJSON))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	event, err := contract.ParseLog(log)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if event.Name != "Transfer" || event.Args["from"].(*rpctypes.EtherAddress).String() != boundContractHolder {
+		t.Errorf("wrong event [Expected: %v, Actual: %v]", "Transfer from 0x...01", event)
+	}
+
+	log.Topics[0] = log.Topics[1]
+	if _, err := contract.ParseLog(log); err == nil {
+		t.Errorf("unknown event parsed [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestBoundContract_Transact(t *testing.T) {
+	var raw string
+	contract, closeServer := testBoundContract(t, func(request RPCRequest) RPCResponse {
+		switch request.Method {
+		case MethodGas:
+			return RPCResponse{Result: "0xc350"}
+		case MethodGetTransactionCount:
+			return RPCResponse{Result: "0x3"}
+		case MethodSendRawTransaction:
+			raw = request.Params.([]interface{})[0].(string)
+			return RPCResponse{Result: "0x01"}
+		}
+		return RPCResponse{Error: &RPCError{Code: -32601, Message: "method not found"}}
+	})
+	defer closeServer()
+
+	key, _ := crypto.HexToECDSA("4646464646464646464646464646464646464646464646464646464646464646")
+	tx := &RawTransaction{GasPrice: big.NewInt(20000000000), ChainID: big.NewInt(1)}
+
+	signed, err := contract.Transact(tx, key, "transfer", "0x0000000000000000000000000000000000000002", big.NewInt(1000))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if tx.Gas != 50000 || tx.Nonce != 3 || tx.To.String() != boundContractAddress || signed.Raw.Hash() != raw {
+		t.Errorf("wrong transaction [Expected: %v, Actual: %v]", "gas 50000, nonce 3 to the contract", tx)
+	}
+
+	if !strings.HasPrefix(rpctypes.NewHexStringFromBytes(tx.Data).Hash(), "0xa9059cbb") {
+		t.Errorf("wrong data [Expected: %v, Actual: %x]", "transfer call", tx.Data)
+	}
+
+	if _, err := contract.Transact(&RawTransaction{Value: big.NewInt(1)}, key, "transfer", boundContractHolder, 1); err == nil {
+		t.Errorf("value sent to non payable method [Expected: %v, Actual: %v]", "error", err)
+	}
+}
@@ -0,0 +1,381 @@
+package rpc
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+// Cache stores json encoded rpc results by key. Implementations must be safe for concurrent use;
+// LRUCache is an in-memory implementation, on-disk backends only need to implement these two methods.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+}
+
+// CacheConfig decides which results of a cache interceptor are considered final.
+type CacheConfig struct {
+	// Confirmations is the number of blocks which have to be mined on top of a block before results looked up
+	// by its number, and transactions or receipts included in it, are cached. Results looked up by block hash
+	// never change and are cached regardless.
+	Confirmations int64
+	// HeadTTL is how long the head block number requested for the confirmation check is reused.
+	HeadTTL time.Duration
+}
+
+// DefaultCacheConfig waits for 12 confirmations and requests the head at most every 5s.
+func DefaultCacheConfig() *CacheConfig {
+	return &CacheConfig{
+		Confirmations: 12,
+		HeadTTL:       5 * time.Second,
+	}
+}
+
+// LRUCache is a Cache which holds up to a fixed number of results and evicts the least recently used one.
+type LRUCache struct {
+	capacity int
+	mu       sync.Mutex
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).value, true
+}
+
+func (c *LRUCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		element.Value.(*lruEntry).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// UseCache adds a cache interceptor to the client, see NewCacheInterceptor. A nil config uses DefaultCacheConfig().
+func (client *Client) UseCache(cache Cache, config *CacheConfig) {
+	client.Use(NewCacheInterceptor(cache, config))
+}
+
+// NewCacheInterceptor returns an interceptor which serves results that can no longer change from the cache:
+//	eth_getBlockByHash,
+//	eth_getBlockByNumber for a block number,
+//	eth_getTransactionByHash and eth_getTransactionReceipt of mined transactions,
+//	eth_getBalance, eth_getCode, eth_getStorageAt, eth_getTransactionCount and eth_call at a block number or hash.
+// Requests for "latest", "pending" or "earliest", errors and null results are never cached.
+func NewCacheInterceptor(cache Cache, config *CacheConfig) Interceptor {
+	if config == nil {
+		config = DefaultCacheConfig()
+	}
+
+	c := &cacheInterceptor{cache: cache, config: config}
+
+	return InterceptorFuncs{Call: c.interceptCall, Batch: c.interceptBatch}
+}
+
+type cacheInterceptor struct {
+	cache  Cache
+	config *CacheConfig
+
+	mu       sync.Mutex
+	head     int64
+	headTime time.Time
+}
+
+// blockRef is the block a request or result refers to.
+type blockRef struct {
+	cacheable bool  // false for tags and unknown methods
+	byHash    bool  // immutable regardless of confirmations
+	number    int64 // only valid if !byHash
+}
+
+// headFunc requests the head block number for the confirmation check.
+type headFunc func(ctx context.Context) (*RPCResponse, error)
+
+func (c *cacheInterceptor) interceptCall(ctx context.Context, request *RPCRequest, next CallFunc) (*RPCResponse, error) {
+	key, ok := cacheKey(request)
+	if !ok {
+		return next(ctx, request)
+	}
+
+	if response, ok := c.lookup(key, request.ID); ok {
+		return response, nil
+	}
+
+	response, err := next(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	c.store(ctx, key, request, response, func(ctx context.Context) (*RPCResponse, error) {
+		return next(ctx, &RPCRequest{JSONRPC: "2.0", ID: request.ID, Method: MethodEthBlockNumber})
+	})
+
+	return response, nil
+}
+
+func (c *cacheInterceptor) interceptBatch(ctx context.Context, requests []interface{}, next BatchFunc) ([]RPCResponse, error) {
+	cached := make([]RPCResponse, 0)
+	remaining := make([]interface{}, 0, len(requests))
+	keys := make(map[uint]string)
+	byID := make(map[uint]*RPCRequest)
+
+	for _, r := range requests {
+		request, ok := r.(*RPCRequest)
+		if !ok {
+			remaining = append(remaining, r)
+			continue
+		}
+
+		key, ok := cacheKey(request)
+		if !ok {
+			remaining = append(remaining, r)
+			continue
+		}
+
+		if response, ok := c.lookup(key, request.ID); ok {
+			cached = append(cached, *response)
+			continue
+		}
+
+		keys[request.ID] = key
+		byID[request.ID] = request
+		remaining = append(remaining, r)
+	}
+
+	if len(remaining) == 0 {
+		return cached, nil
+	}
+
+	responses, err := next(ctx, remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	head := func(ctx context.Context) (*RPCResponse, error) {
+		responses, err := next(ctx, []interface{}{&RPCRequest{JSONRPC: "2.0", Method: MethodEthBlockNumber}})
+		if err != nil || len(responses) != 1 {
+			return nil, err
+		}
+		return &responses[0], nil
+	}
+
+	for k := range responses {
+		if key, ok := keys[responses[k].ID]; ok {
+			c.store(ctx, key, byID[responses[k].ID], &responses[k], head)
+		}
+	}
+
+	return append(cached, responses...), nil
+}
+
+func (c *cacheInterceptor) lookup(key string, id uint) (*RPCResponse, bool) {
+	value, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	var result interface{}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&result); err != nil {
+		return nil, false
+	}
+
+	return &RPCResponse{JSONRPC: "2.0", ID: id, Result: result}, true
+}
+
+// store caches the result if it is final. head requests the current block number if the confirmations must be checked.
+func (c *cacheInterceptor) store(ctx context.Context, key string, request *RPCRequest, response *RPCResponse, head headFunc) {
+	if response.Error != nil || response.Result == nil {
+		return
+	}
+
+	ref := blockOf(request, response.Result)
+	if !ref.cacheable {
+		return
+	}
+
+	if !ref.byHash && c.config.Confirmations > 0 {
+		current, ok := c.headNumber(ctx, head)
+		if !ok || current-ref.number < c.config.Confirmations {
+			return
+		}
+	}
+
+	value, err := json.Marshal(response.Result)
+	if err != nil {
+		return
+	}
+
+	c.cache.Set(key, value)
+}
+
+func (c *cacheInterceptor) headNumber(ctx context.Context, head headFunc) (int64, bool) {
+	c.mu.Lock()
+	if !c.headTime.IsZero() && time.Since(c.headTime) < c.config.HeadTTL {
+		defer c.mu.Unlock()
+		return c.head, true
+	}
+	c.mu.Unlock()
+
+	response, err := head(ctx)
+	if err != nil || response == nil || response.Error != nil {
+		return 0, false
+	}
+
+	number, ok := hexNumber(response.Result)
+	if !ok {
+		return 0, false
+	}
+
+	c.mu.Lock()
+	c.head = number
+	c.headTime = time.Now()
+	c.mu.Unlock()
+
+	return number, true
+}
+
+// cacheKey returns the key of cacheable methods: the method followed by the json encoded params.
+func cacheKey(request *RPCRequest) (string, bool) {
+	switch request.Method {
+	case MethodGetBlockByHash, MethodGetBlockByNumber, MethodGetTransactionByHash, MethodGetTransactionReceipt,
+		MethodGetBalance, MethodGetCode, MethodGetStorageAt, MethodGetTransactionCount, MethodEthCall:
+	default:
+		return "", false
+	}
+
+	params, err := json.Marshal(request.Params)
+	if err != nil {
+		return "", false
+	}
+
+	return request.Method + string(params), true
+}
+
+// blockOf returns the block a request refers to: the block parameter, or the block of a mined transaction.
+func blockOf(request *RPCRequest, result interface{}) blockRef {
+	params, _ := request.Params.([]interface{})
+
+	switch request.Method {
+	case MethodGetBlockByHash:
+		return blockRef{cacheable: true, byHash: true}
+	case MethodGetTransactionByHash, MethodGetTransactionReceipt:
+		fields, ok := result.(map[string]interface{})
+		if !ok {
+			return blockRef{}
+		}
+		number, ok := hexNumber(fields["blockNumber"])
+		return blockRef{cacheable: ok, number: number}
+	}
+
+	if request.Method == MethodGetBlockByNumber && len(params) > 0 {
+		// the block number is followed by the full transactions flag
+		return blockParam(params[0])
+	}
+
+	if request.Method == MethodEthCall && len(params) > 1 {
+		// the optional state overrides follow the block
+		return blockParam(params[1])
+	}
+
+	if len(params) == 0 {
+		return blockRef{}
+	}
+
+	return blockParam(params[len(params)-1])
+}
+
+// blockParam classifies a block parameter: a block number, a block hash, an EIP-1898 object or a tag.
+func blockParam(param interface{}) blockRef {
+	if s, ok := param.(string); ok {
+		if len(strings.TrimPrefix(s, "0x")) == 64 {
+			return blockRef{cacheable: true, byHash: true}
+		}
+		number, ok := hexNumber(s)
+		return blockRef{cacheable: ok, number: number}
+	}
+
+	js, err := json.Marshal(param)
+	if err != nil {
+		return blockRef{}
+	}
+
+	object := struct {
+		BlockHash   string `json:"blockHash"`
+		BlockNumber string `json:"blockNumber"`
+	}{}
+	if err := json.Unmarshal(js, &object); err != nil {
+		return blockRef{}
+	}
+
+	if object.BlockHash != "" {
+		return blockRef{cacheable: true, byHash: true}
+	}
+	if object.BlockNumber != "" {
+		return blockParam(object.BlockNumber)
+	}
+
+	return blockRef{}
+}
+
+func hexNumber(value interface{}) (int64, bool) {
+	s, ok := value.(string)
+	if !ok || !strings.HasPrefix(s, "0x") {
+		return 0, false
+	}
+
+	hs, err := rpctypes.NewHexString(s)
+	if err != nil {
+		return 0, false
+	}
+
+	return hs.Int64(), true
+}
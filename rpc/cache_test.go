@@ -0,0 +1,113 @@
+package rpc
+
+import (
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+// cacheTestServer answers eth_blockNumber with head, eth_getBalance with 0x1, eth_getBlockByNumber with a block
+// and counts the balance and block requests.
+func cacheTestServer(head string) (*httptest.Server, *int32) {
+	calls := new(int32)
+
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		switch request.Method {
+		case MethodGetBalance:
+			atomic.AddInt32(calls, 1)
+			return RPCResponse{Result: "0x1"}
+		case MethodGetBlockByNumber:
+			atomic.AddInt32(calls, 1)
+			return RPCResponse{Result: map[string]interface{}{"number": request.Params.([]interface{})[0]}}
+		}
+		return RPCResponse{Result: head}
+	})
+
+	return server, calls
+}
+
+func TestClient_CacheConfirmations(t *testing.T) {
+	server, calls := cacheTestServer("0x64")
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.UseCache(NewLRUCache(10), &CacheConfig{Confirmations: 10})
+
+	for _, block := range []string{"latest", "latest", "0x60", "0x60", "0x10", "0x10"} {
+		if _, err := client.Call(MethodGetBalance, "0x0000000000000000000000000000000000000001", block); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	// latest and the unconfirmed block 0x60 are requested twice, the confirmed block 0x10 once
+	if *calls != 5 {
+		t.Errorf("wrong number of requests [Expected: %v, Actual: %v]", 5, *calls)
+	}
+}
+
+func TestClient_CacheBlockByNumber(t *testing.T) {
+	server, calls := cacheTestServer("0x64")
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.UseCache(NewLRUCache(10), &CacheConfig{})
+
+	for _, block := range []string{"0x10", "0x10", "latest", "latest"} {
+		if _, err := client.Call(MethodGetBlockByNumber, block, false); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	// the block 0x10 is served from the cache the second time, latest is requested twice
+	if *calls != 3 {
+		t.Errorf("wrong number of requests [Expected: %v, Actual: %v]", 3, *calls)
+	}
+}
+
+func TestClient_CacheBatch(t *testing.T) {
+	server, calls := cacheTestServer("0x64")
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.UseCache(NewLRUCache(10), &CacheConfig{})
+
+	address := "0x0000000000000000000000000000000000000001"
+
+	if _, err := client.Eth.GetBalance(address, rpctypes.QuantityBlock(16)); err != nil {
+		t.Error(err)
+		return
+	}
+
+	batch := client.NewBatch()
+	cached := batch.GetBalance(address, rpctypes.QuantityBlock(16))
+	batch.GetBalance(address, rpctypes.QuantityBlock(17))
+
+	if err := batch.Execute(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if value, err := cached.Get(); err != nil || value.BigInt().Int64() != 1 {
+		t.Errorf("wrong cached balance [Expected: %v, Actual: %v, %v]", 1, value, err)
+	}
+
+	if *calls != 2 {
+		t.Errorf("wrong number of requests [Expected: %v, Actual: %v]", 2, *calls)
+	}
+}
+
+func TestLRUCache_Evict(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", []byte("1"))
+	cache.Set("b", []byte("2"))
+	cache.Get("a")
+	cache.Set("c", []byte("3"))
+
+	if _, ok := cache.Get("b"); ok || cache.Len() != 2 {
+		t.Errorf("least recently used entry not evicted [Expected: %v entries, Actual: %v entries]", 2, cache.Len())
+	}
+}
@@ -1,13 +1,13 @@
 package rpc
 
 import (
-	"bytes"
+	"context"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
 )
@@ -51,6 +51,7 @@ type RPCResponse struct {
 // if you are interested in the response of a specific request use: GetResponseOf(request)
 type BatchResponse struct {
 	rpcResponses []RPCResponse
+	index        map[uint]int
 }
 
 // RPCError represents a jsonrpc error object if an rpc error occurred.
@@ -66,10 +67,15 @@ func (e *RPCError) Error() string {
 	return strconv.Itoa(e.Code) + ": " + e.Message
 }
 
-// ParityRPCClient sends jsonrpc requests over http to the provided rpc backend.
+// ParityRPCClient sends jsonrpc requests over http, websockets or ipc to the provided rpc backend.
 // ParityRPCClient is created using the factory function NewRPCClient().
 type Client struct {
 	endpoint        string
+	transport       Transport
+	retryPolicy     *RetryPolicy
+	limits          rateLimits
+	interceptors    []Interceptor
+	interceptorsMu  sync.RWMutex
 	httpClient      *http.Client
 	customHeaders   map[string]string
 	autoIncrementID bool
@@ -83,6 +89,10 @@ type Client struct {
 
 // NewRPCClient returns a new ParityRPCClient instance with default configuration (no custom headers, default http.Client, autoincrement ids).
 // Endpoint is the rpc-service url to which the rpc requests are sent.
+//
+// The transport is selected by the endpoint: ws:// and wss:// urls use a single persistent websocket connection,
+// absolute paths and paths ending in .ipc (e.g. "/home/parity/.local/share/io.parity.ethereum/jsonrpc.ipc") use a
+// unix domain socket. Both are dialed lazily on the first request. Every other endpoint is served over http POST.
 func NewRPCClient(endpoint string) *Client {
 	client := &Client{
 		endpoint:        endpoint,
@@ -92,12 +102,41 @@ func NewRPCClient(endpoint string) *Client {
 		customHeaders:   make(map[string]string),
 	}
 
+	switch {
+	case strings.HasPrefix(endpoint, "ws://"), strings.HasPrefix(endpoint, "wss://"):
+		client.transport = newWebsocketTransport(client)
+	case isIPCEndpoint(endpoint):
+		client.transport = newIPCTransport(endpoint)
+	default:
+		client.transport = newHTTPTransport(client)
+	}
+
+	client.initModules()
+
+	return client
+}
+
+// NewRPCClientWithTransport returns a new ParityRPCClient instance which sends all requests through the given transport,
+// e.g. a Pool of several backends. SetHTTPClient() and the custom headers of the returned client have no effect.
+func NewRPCClientWithTransport(transport Transport) *Client {
+	client := &Client{
+		transport:       transport,
+		httpClient:      http.DefaultClient,
+		autoIncrementID: true,
+		nextID:          0,
+		customHeaders:   make(map[string]string),
+	}
+
+	client.initModules()
+
+	return client
+}
+
+func (client *Client) initModules() {
 	client.Web3 = Web3{client: client}
 	client.Eth = Eth{client: client}
 	client.Net = Net{client: client}
 	client.Personal = Personal{client: client}
-
-	return client
 }
 
 // NewRPCRequestObject creates and returns a raw RPCRequest structure.
@@ -140,84 +179,67 @@ func (client *Client) NewRPCNotificationObject(method string, params ...interfac
 	return &rpcNotification
 }
 
-// Call sends an jsonrpc request over http to the rpc-service url that was provided on Client creation.
+// Call sends an jsonrpc request over the client's transport to the rpc-service url that was provided on Client creation.
 //
-// If something went wrong on the network / http level or if json parsing failed it returns an error.
+// If something went wrong on the network / transport level or if json parsing failed it returns an error.
 //
 // If something went wrong on the rpc-service / protocol level the Error field of the returned RPCResponse is set
 // and contains information about the error.
 //
 // If the request was successful the Error field is nil and the Result field of the RPCRespnse struct contains the rpc result.
 func (client *Client) Call(method string, params ...interface{}) (*RPCResponse, error) {
+	return client.CallContext(context.Background(), method, params...)
+}
+
+// CallContext is like Call() but carries the given context down to the transport.
+// If the context is canceled or its deadline is exceeded before the response arrived, the context's error is returned.
+func (client *Client) CallContext(ctx context.Context, method string, params ...interface{}) (*RPCResponse, error) {
 	var p interface{}
 
 	if len(params) != 0 {
 		p = params
 	}
 
-	httpRequest, err := client.newRequest(false, method, p)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return client.doCall(httpRequest)
+	return client.call(ctx, client.newRequest(method, p))
 }
 
-// CallNamed sends an jsonrpc request over http to the rpc-service url that was provided on Client creation.
+// CallNamed sends an jsonrpc request over the client's transport to the rpc-service url that was provided on Client creation.
 // This differs from Call() by sending named, rather than positional, arguments.
 //
-// If something went wrong on the network / http level or if json parsing failed it returns an error.
+// If something went wrong on the network / transport level or if json parsing failed it returns an error.
 //
 // If something went wrong on the rpc-service / protocol level the Error field of the returned RPCResponse is set
 // and contains information about the error.
 //
 // If the request was successful the Error field is nil and the Result field of the RPCRespnse struct contains the rpc result.
 func (client *Client) CallNamed(method string, params map[string]interface{}) (*RPCResponse, error) {
-	httpRequest, err := client.newRequest(false, method, params)
-	if err != nil {
-		return nil, err
-	}
-	return client.doCall(httpRequest)
+	return client.CallNamedContext(context.Background(), method, params)
 }
 
-func (client *Client) doCall(req *http.Request) (*RPCResponse, error) {
-	httpResponse, err := client.httpClient.Do(req)
-
-	if err != nil {
-		return nil, err
-	}
-
-	defer httpResponse.Body.Close()
-	decoder := json.NewDecoder(httpResponse.Body)
-	decoder.UseNumber()
-	rpcResponse := RPCResponse{}
-	err = decoder.Decode(&rpcResponse)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return &rpcResponse, nil
+// CallNamedContext is like CallNamed() but carries the given context down to the transport.
+func (client *Client) CallNamedContext(ctx context.Context, method string, params map[string]interface{}) (*RPCResponse, error) {
+	return client.call(ctx, client.newRequest(method, params))
 }
 
 // Notification sends a jsonrpc request to the rpc-service. The difference to Call() is that this request does not expect a response.
 // The ID field of the request is omitted.
 func (client *Client) Notification(method string, params ...interface{}) error {
-	if len(params) == 0 {
-		params = nil
-	}
-	httpRequest, err := client.newRequest(true, method, params)
-	if err != nil {
-		return err
-	}
+	return client.NotificationContext(context.Background(), method, params...)
+}
 
-	httpResponse, err := client.httpClient.Do(httpRequest)
-	if err != nil {
-		return err
+// NotificationContext is like Notification() but carries the given context down to the transport.
+func (client *Client) NotificationContext(ctx context.Context, method string, params ...interface{}) error {
+	var p interface{}
+
+	if len(params) != 0 {
+		p = params
 	}
-	defer httpResponse.Body.Close()
-	return nil
+
+	return client.notify(ctx, &RPCNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  p,
+	})
 }
 
 // Batch sends a jsonrpc batch request to the rpc-service.
@@ -227,6 +249,11 @@ func (client *Client) Notification(method string, params ...interface{}) error {
 //
 // The batch requests returns a list of RPCResponse structs.
 func (client *Client) Batch(requests ...interface{}) (*BatchResponse, error) {
+	return client.BatchContext(context.Background(), requests...)
+}
+
+// BatchContext is like Batch() but carries the given context down to the transport.
+func (client *Client) BatchContext(ctx context.Context, requests ...interface{}) (*BatchResponse, error) {
 	for _, r := range requests {
 		switch r := r.(type) {
 		default:
@@ -236,26 +263,25 @@ func (client *Client) Batch(requests ...interface{}) (*BatchResponse, error) {
 		}
 	}
 
-	httpRequest, err := client.newBatchRequest(requests...)
+	rpcResponses, err := client.batch(ctx, requests)
 	if err != nil {
 		return nil, err
 	}
 
-	httpResponse, err := client.httpClient.Do(httpRequest)
-	if err != nil {
-		return nil, err
+	index := make(map[uint]int, len(rpcResponses))
+	for k, r := range rpcResponses {
+		if _, ok := index[r.ID]; !ok {
+			index[r.ID] = k
+		}
 	}
-	defer httpResponse.Body.Close()
 
-	rpcResponses := []RPCResponse{}
-	decoder := json.NewDecoder(httpResponse.Body)
-	decoder.UseNumber()
-	err = decoder.Decode(&rpcResponses)
-	if err != nil {
-		return nil, err
-	}
+	return &BatchResponse{rpcResponses: rpcResponses, index: index}, nil
+}
 
-	return &BatchResponse{rpcResponses: rpcResponses}, nil
+// Close releases the resources held by the client's transport, e.g. an open websocket connection.
+// Calls made after Close() return an error for persistent transports.
+func (client *Client) Close() error {
+	return client.transport.Close()
 }
 
 // SetAutoIncrementID if set to true, the id field of an rpcjson request will be incremented automatically
@@ -272,6 +298,7 @@ func (client *Client) SetNextID(id uint) {
 
 // SetCustomHeader is used to set a custom header for each rpc request.
 // You could for example set the Authorization Bearer here.
+// For websocket endpoints the headers are sent with the opening handshake.
 func (client *Client) SetCustomHeader(key string, value string) {
 	client.customHeaders[key] = value
 }
@@ -294,6 +321,7 @@ func (client *Client) SetBasicAuth(username string, password string) {
 
 // SetHTTPClient can be used to set a custom http.Client.
 // This can be useful for example if you want to customize the http.Client behaviour (e.g. proxy settings)
+// It has no effect on websocket endpoints.
 func (client *Client) SetHTTPClient(httpClient *http.Client) {
 	if httpClient == nil {
 		panic("httpClient cannot be nil")
@@ -301,73 +329,20 @@ func (client *Client) SetHTTPClient(httpClient *http.Client) {
 	client.httpClient = httpClient
 }
 
-func (client *Client) newRequest(notification bool, method string, params interface{}) (*http.Request, error) {
-	// TODO: easier way to remove ID from RPCRequest without extra struct
-	var rpcRequest interface{}
-	if notification {
-		rpcNotification := RPCNotification{
-			JSONRPC: "2.0",
-			Method:  method,
-			Params:  params,
-		}
-		rpcRequest = rpcNotification
-	} else {
-		client.idMutex.Lock()
-		request := RPCRequest{
-			ID:      client.nextID,
-			JSONRPC: "2.0",
-			Method:  method,
-			Params:  params,
-		}
-		if client.autoIncrementID == true {
-			client.nextID++
-		}
-		client.idMutex.Unlock()
-		rpcRequest = request
-	}
-
-	body, err := json.Marshal(rpcRequest)
-
-	if err != nil {
-		return nil, err
-	}
-
-	request, err := http.NewRequest("POST", client.endpoint, bytes.NewReader(body))
-
-	if err != nil {
-		return nil, err
-	}
-
-	for k, v := range client.customHeaders {
-		request.Header.Add(k, v)
-	}
-
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("Accept", "application/json")
-
-	return request, nil
-}
-
-func (client *Client) newBatchRequest(requests ...interface{}) (*http.Request, error) {
-
-	body, err := json.Marshal(requests)
-	if err != nil {
-		return nil, err
-	}
-
-	request, err := http.NewRequest("POST", client.endpoint, bytes.NewReader(body))
-	if err != nil {
-		return nil, err
+func (client *Client) newRequest(method string, params interface{}) *RPCRequest {
+	client.idMutex.Lock()
+	request := RPCRequest{
+		ID:      client.nextID,
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
 	}
-
-	for k, v := range client.customHeaders {
-		request.Header.Add(k, v)
+	if client.autoIncrementID == true {
+		client.nextID++
 	}
+	client.idMutex.Unlock()
 
-	request.Header.Add("Content-Type", "application/json")
-	request.Header.Add("Accept", "application/json")
-
-	return request, nil
+	return &request
 }
 
 // UpdateRequestID updates the ID of an RPCRequest structure.
@@ -409,10 +384,8 @@ func (batchResponse *BatchResponse) GetResponseOf(request *RPCRequest) (*RPCResp
 		return nil, errors.New("parameter cannot be nil")
 	}
 
-	for _, elem := range batchResponse.rpcResponses {
-		if elem.ID == request.ID {
-			return &elem, nil
-		}
+	if k, ok := batchResponse.index[request.ID]; ok {
+		return &batchResponse.rpcResponses[k], nil
 	}
 
 	return nil, fmt.Errorf("element with id %d not found", request.ID)
@@ -431,3 +404,13 @@ func checkRPCError(response *RPCResponse, err error) (*RPCResponse, error) {
 
 	return response, nil
 }
+
+// contextError prefers the context's error over the transport error once the context is done,
+// so that callers can compare against context.Canceled and context.DeadlineExceeded.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_CallContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := NewRPCClient(server.URL).Eth.BlockNumberContext(ctx)
+
+	if err != context.DeadlineExceeded {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", context.DeadlineExceeded, err)
+	}
+}
+
+func TestClient_BatchContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewRPCClient(server.URL)
+	_, err := client.BatchContext(ctx, client.NewRPCRequestObject(MethodEthBlockNumber))
+
+	if err != context.Canceled {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", context.Canceled, err)
+	}
+}
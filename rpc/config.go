@@ -6,3 +6,11 @@ const RPCEndpointLocalHost = "http://localhost:8545"
 const EndpointRemote = "http://ec2-54-93-108-139.eu-central-1.compute.amazonaws.com:8545"
 
 var NotImplemented = errors.New("rpc method not implemented yet")
+
+var TransportClosed = errors.New("rpc transport is closed")
+
+var SubscriptionsNotSupported = errors.New("subscriptions require a websocket or ipc endpoint")
+
+var RateLimited = errors.New("rpc request rejected by client side rate limit")
+
+var BatchNotExecuted = errors.New("batch has not been executed yet")
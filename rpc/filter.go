@@ -0,0 +1,270 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+const DefaultFilterPollInterval = 4 * time.Second
+
+// FilterChanges is the result of eth_getFilterChanges. Logs is set for log filters (eth_newFilter),
+// Hashes for block and pending transaction filters.
+type FilterChanges struct {
+	Logs   []rpctypes.EtherLog
+	Hashes []rpctypes.HexString
+}
+
+// Len returns the number of changes.
+func (changes *FilterChanges) Len() int {
+	return len(changes.Logs) + len(changes.Hashes)
+}
+
+// getFilterChangesFromResponse decodes the result of eth_getFilterChanges, a list of either log objects or hashes.
+// A null result is treated as no changes.
+func getFilterChangesFromResponse(result interface{}) (*FilterChanges, error) {
+	changes := new(FilterChanges)
+
+	if result == nil {
+		return changes, nil
+	}
+
+	list, ok := result.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("could not parse filter changes from %v", result)
+	}
+
+	if len(list) == 0 {
+		return changes, nil
+	}
+
+	if _, ok := list[0].(string); ok {
+		hashes := make([]string, len(list))
+		for k, v := range list {
+			if hashes[k], ok = v.(string); !ok {
+				return nil, fmt.Errorf("could not parse hash from %v", v)
+			}
+		}
+
+		hsl, err := rpctypes.HexStringListFromString(hashes)
+		if err != nil {
+			return nil, err
+		}
+		changes.Hashes = hsl
+
+		return changes, nil
+	}
+
+	js, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := new(rpctypes.EtherLogRaw).FromJSONArray(js)
+	if err != nil {
+		return nil, err
+	}
+	changes.Logs = logs
+
+	return changes, nil
+}
+
+// IsFilterNotFoundError reports whether the node does not know the filter, e.g. because it was not polled
+// for too long or the node restarted.
+func IsFilterNotFoundError(err error) bool {
+	return rpcErrorContains(err, []string{"filter not found"})
+}
+
+// FilterWatcher polls a filter with eth_getFilterChanges and delivers the changes on a channel, in the order the node
+// returned them. If the node reports that the filter is not found, it is installed again; changes which happened
+// while the filter was gone are lost. Any other error is sent on the Err channel and ends the watch.
+type FilterWatcher struct {
+	client   *Client
+	install  func(ctx context.Context) (string, error)
+	send     func(changes *FilterChanges, quit <-chan struct{}) bool
+	interval time.Duration
+
+	mu   sync.Mutex
+	id   string
+	quit chan struct{}
+	done chan struct{}
+	err  chan error
+	stop sync.Once
+}
+
+// NewLogFilterWatcher installs a log filter (eth_newFilter) and delivers its new logs on the channel every interval.
+func NewLogFilterWatcher(client *Client, params *NewFilterParams, interval time.Duration, logs chan<- rpctypes.EtherLog) (*FilterWatcher, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return newFilterWatcher(
+		client,
+		interval,
+		func(ctx context.Context) (string, error) {
+			return client.RequestStringContext(ctx, MethodNewFilter, params.ToMap())
+		},
+		func(changes *FilterChanges, quit <-chan struct{}) bool {
+			for _, log := range changes.Logs {
+				select {
+				case logs <- log:
+				case <-quit:
+					return false
+				}
+			}
+			return true
+		},
+	)
+}
+
+// NewBlockFilterWatcher installs a block filter (eth_newBlockFilter) and delivers the hashes of new blocks on the channel every interval.
+func NewBlockFilterWatcher(client *Client, interval time.Duration, hashes chan<- rpctypes.HexString) (*FilterWatcher, error) {
+	return newFilterWatcher(
+		client,
+		interval,
+		func(ctx context.Context) (string, error) {
+			return client.RequestStringContext(ctx, MethodNewBlockFilter)
+		},
+		sendHashes(hashes),
+	)
+}
+
+// NewPendingTransactionFilterWatcher installs a pending transaction filter (eth_newPendingTransactionFilter)
+// and delivers the hashes of new pending transactions on the channel every interval.
+func NewPendingTransactionFilterWatcher(client *Client, interval time.Duration, hashes chan<- rpctypes.HexString) (*FilterWatcher, error) {
+	return newFilterWatcher(
+		client,
+		interval,
+		func(ctx context.Context) (string, error) {
+			return client.RequestStringContext(ctx, MethodNewPendingTransactionFilter)
+		},
+		sendHashes(hashes),
+	)
+}
+
+func sendHashes(hashes chan<- rpctypes.HexString) func(*FilterChanges, <-chan struct{}) bool {
+	return func(changes *FilterChanges, quit <-chan struct{}) bool {
+		for _, hash := range changes.Hashes {
+			select {
+			case hashes <- hash:
+			case <-quit:
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func newFilterWatcher(client *Client, interval time.Duration, install func(context.Context) (string, error), send func(*FilterChanges, <-chan struct{}) bool) (*FilterWatcher, error) {
+	if interval <= 0 {
+		interval = DefaultFilterPollInterval
+	}
+
+	id, err := install(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	w := &FilterWatcher{
+		client:   client,
+		install:  install,
+		send:     send,
+		interval: interval,
+		id:       id,
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+		err:      make(chan error, 1),
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+// ID returns the id of the filter in the node. It changes when the filter is installed again.
+func (w *FilterWatcher) ID() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.id
+}
+
+// Err returns the error channel of the watcher. At most one error is sent on it, after which the watch ends.
+func (w *FilterWatcher) Err() <-chan error {
+	return w.err
+}
+
+// UninstallFilter stops the polling and removes the filter from the node. It is safe to call it more than once.
+func (w *FilterWatcher) UninstallFilter() error {
+	return w.UninstallFilterContext(context.Background())
+}
+
+// UninstallFilterContext is like UninstallFilter but takes a context.Context for deadlines and cancellation.
+func (w *FilterWatcher) UninstallFilterContext(ctx context.Context) error {
+	uninstall := false
+	w.stop.Do(func() {
+		close(w.quit)
+		uninstall = true
+	})
+	<-w.done
+
+	if !uninstall {
+		return nil
+	}
+
+	_, err := w.client.Eth.UninstallFilterContext(ctx, w.ID())
+
+	return err
+}
+
+func (w *FilterWatcher) run() {
+	defer close(w.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-w.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.quit:
+			return
+		}
+
+		changes, err := w.client.Eth.GetFilterChangesContext(ctx, w.ID())
+		if IsFilterNotFoundError(err) {
+			var id string
+			if id, err = w.install(ctx); err == nil {
+				w.mu.Lock()
+				w.id = id
+				w.mu.Unlock()
+				continue
+			}
+		}
+
+		if err != nil {
+			select {
+			case <-w.quit:
+			default:
+				w.err <- err
+			}
+			return
+		}
+
+		if !w.send(changes, w.quit) {
+			return
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+const testFilterLog = `{"address":"0x7f0d15c7faae65896648c8273b6d7e43f58fa842","blockHash":"0x8243343df08b9751f5ca0c5f8c9c0460d8a9b6351066fae0acbd4d3e776de8bb","blockNumber":"0x1b4","data":"0x","logIndex":"0x1","removed":false,"topics":["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"],"transactionHash":"0xdf829c5a142f1fccd7d8216c5785ac562ff41e2dcfdf5785ac562ff41e2dcf","transactionIndex":"0x0"}`
+
+func TestGetFilterChangesFromResponse(t *testing.T) {
+	var logs interface{}
+	json.Unmarshal([]byte("["+testFilterLog+"]"), &logs)
+
+	tests := []struct {
+		name   string
+		result interface{}
+		logs   int
+		hashes int
+	}{
+		{"null", nil, 0, 0},
+		{"empty", []interface{}{}, 0, 0},
+		{"hashes", []interface{}{"0x8243343df08b9751f5ca0c5f8c9c0460d8a9b6351066fae0acbd4d3e776de8bb", "0x01"}, 0, 2},
+		{"logs", logs, 1, 0},
+	}
+
+	for _, test := range tests {
+		changes, err := getFilterChangesFromResponse(test.result)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if len(changes.Logs) != test.logs || len(changes.Hashes) != test.hashes {
+			t.Errorf("wrong changes for %v [Expected: %v logs %v hashes, Actual: %v logs %v hashes]", test.name, test.logs, test.hashes, len(changes.Logs), len(changes.Hashes))
+		}
+	}
+
+	if _, err := getFilterChangesFromResponse("0x1"); err == nil {
+		t.Errorf("no list accepted [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+// filterServer answers eth_getFilterChanges of the installed filter with changes and forgets it after the first poll.
+func filterServer(changes interface{}) (*httptest.Server, *filterServerState) {
+	state := new(filterServerState)
+
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		state.mu.Lock()
+		defer state.mu.Unlock()
+
+		response := RPCResponse{}
+
+		switch request.Method {
+		case MethodNewFilter, MethodNewBlockFilter, MethodNewPendingTransactionFilter:
+			state.installed++
+			state.id = "0x" + string(rune('a'+state.installed))
+			response.Result = state.id
+		case MethodGetFilterChanges:
+			if request.Params.([]interface{})[0] != state.id {
+				response.Error = &RPCError{Code: -32000, Message: "filter not found"}
+				break
+			}
+			state.polls++
+			response.Result = changes
+			if state.polls == 1 {
+				state.id = ""
+			}
+		case MethodUninstallFilter:
+			state.uninstalled = request.Params.([]interface{})[0].(string)
+			response.Result = true
+		}
+
+		return response
+	})
+
+	return server, state
+}
+
+type filterServerState struct {
+	mu          sync.Mutex
+	id          string
+	installed   int
+	polls       int
+	uninstalled string
+}
+
+func TestBlockFilterWatcher(t *testing.T) {
+	server, state := filterServer([]string{"0x01", "0x02"})
+	defer server.Close()
+
+	hashes := make(chan rpctypes.HexString)
+	watcher, err := NewBlockFilterWatcher(NewRPCClient(server.URL), 5*time.Millisecond, hashes)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	received := make([]int64, 0)
+	for len(received) < 4 {
+		select {
+		case hash := <-hashes:
+			received = append(received, hash.Int64())
+		case err := <-watcher.Err():
+			t.Error(err)
+			return
+		case <-time.After(time.Second):
+			t.Errorf("no hashes [Expected: %v, Actual: %v]", 4, len(received))
+			return
+		}
+	}
+
+	id := watcher.ID()
+	if err := watcher.UninstallFilter(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	// the filter is forgotten after the first poll and must have been installed again
+	if state.installed < 2 || state.uninstalled != id {
+		t.Errorf("filter not recreated [Expected: %v installs and uninstall of %v, Actual: %v installs and uninstall of %v]", 2, id, state.installed, state.uninstalled)
+	}
+
+	if received[0] != 1 || received[1] != 2 {
+		t.Errorf("wrong hashes [Expected: %v, Actual: %v]", []int64{1, 2, 1, 2}, received)
+	}
+
+	if err := watcher.UninstallFilter(); err != nil {
+		t.Errorf("second uninstall [Expected: %v, Actual: %v]", nil, err)
+	}
+}
+
+func TestLogFilterWatcher(t *testing.T) {
+	var logs interface{}
+	json.Unmarshal([]byte("["+testFilterLog+"]"), &logs)
+
+	server, _ := filterServer(logs)
+	defer server.Close()
+
+	received := make(chan rpctypes.EtherLog)
+	watcher, err := NewLogFilterWatcher(NewRPCClient(server.URL), &NewFilterParams{FromBlock: rpctypes.QuantityLatest()}, 5*time.Millisecond, received)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer watcher.UninstallFilter()
+
+	select {
+	case log := <-received:
+		if log.BlockNumber != 0x1b4 {
+			t.Errorf("wrong log [Expected: %v, Actual: %v]", 0x1b4, log.BlockNumber)
+		}
+	case err := <-watcher.Err():
+		t.Error(err)
+	case <-time.After(time.Second):
+		t.Errorf("no log [Expected: %v, Actual: %v]", 1, 0)
+	}
+}
+
+func TestFilterWatcher_Error(t *testing.T) {
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		if request.Method == MethodGetFilterChanges {
+			return RPCResponse{Error: &RPCError{Code: -32602, Message: "invalid argument"}}
+		}
+		return RPCResponse{Result: "0x1"}
+	})
+	defer server.Close()
+
+	watcher, err := NewPendingTransactionFilterWatcher(NewRPCClient(server.URL), 5*time.Millisecond, make(chan rpctypes.HexString))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer watcher.UninstallFilter()
+
+	select {
+	case err := <-watcher.Err():
+		if IsFilterNotFoundError(err) {
+			t.Errorf("wrong error [Expected: %v, Actual: %v]", "invalid argument", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("no error [Expected: %v, Actual: %v]", "invalid argument", nil)
+	}
+}
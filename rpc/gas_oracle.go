@@ -0,0 +1,468 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+// GasSpeed selects one of the suggestions of GasPrices.
+type GasSpeed int
+
+const (
+	GasStandard GasSpeed = iota
+	GasSlow
+	GasFast
+)
+
+// GasPriceStrategy suggests the gas prices of new transactions. GasOracle and NodeGasPriceStrategy implement it,
+// NonceManager.GasStrategy uses it to price transactions which are sent without gas price.
+type GasPriceStrategy interface {
+	SuggestGasPrices(ctx context.Context) (*GasPrices, error)
+}
+
+// GasPriceSuggestion holds the prices of one speed. MaxFeePerGas and MaxPriorityFeePerGas are nil
+// if the network does not support EIP-1559.
+type GasPriceSuggestion struct {
+	GasPrice             *big.Int
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerGas         *big.Int
+}
+
+// Apply sets the prices of the transaction according to its type: GasPrice for legacy and access list transactions,
+// MaxFeePerGas and MaxPriorityFeePerGas for dynamic fee transactions (both GasPrice if no EIP-1559 prices are known).
+func (suggestion GasPriceSuggestion) Apply(tx *RawTransaction) {
+	if tx.Type != rpctypes.TransactionTypeDynamicFee {
+		tx.GasPrice = copyBigInt(suggestion.GasPrice)
+		return
+	}
+
+	if suggestion.MaxFeePerGas == nil {
+		tx.MaxFeePerGas = copyBigInt(suggestion.GasPrice)
+		tx.MaxPriorityFeePerGas = copyBigInt(suggestion.GasPrice)
+		return
+	}
+
+	tx.MaxFeePerGas = copyBigInt(suggestion.MaxFeePerGas)
+	tx.MaxPriorityFeePerGas = copyBigInt(suggestion.MaxPriorityFeePerGas)
+}
+
+// GasPrices are the suggestions computed at block BlockNumber.
+type GasPrices struct {
+	BlockNumber int64
+	BaseFee     *big.Int // the estimated base fee of the next block, nil before the london fork
+	Slow        GasPriceSuggestion
+	Standard    GasPriceSuggestion
+	Fast        GasPriceSuggestion
+}
+
+// Suggestion returns the suggestion of the given speed.
+func (prices *GasPrices) Suggestion(speed GasSpeed) GasPriceSuggestion {
+	switch speed {
+	case GasSlow:
+		return prices.Slow
+	case GasFast:
+		return prices.Fast
+	default:
+		return prices.Standard
+	}
+}
+
+// NodeGasPriceStrategy suggests the eth_gasPrice of the node for every speed.
+type NodeGasPriceStrategy struct {
+	client *Client
+}
+
+func NewNodeGasPriceStrategy(client *Client) *NodeGasPriceStrategy {
+	return &NodeGasPriceStrategy{client: client}
+}
+
+func (strategy *NodeGasPriceStrategy) SuggestGasPrices(ctx context.Context) (*GasPrices, error) {
+	gasPrice, err := strategy.client.Eth.GasPriceContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion := GasPriceSuggestion{GasPrice: gasPrice.BigInt()}
+
+	return &GasPrices{BlockNumber: -1, Slow: suggestion, Standard: suggestion, Fast: suggestion}, nil
+}
+
+type GasOracleConfig struct {
+	Blocks             int     // number of recent blocks which are sampled
+	SlowPercentile     float64 // percentiles of the prices paid in the sampled blocks, 0 - 100
+	StandardPercentile float64
+	FastPercentile     float64
+	BaseFeeMultiplier  int64 // MaxFeePerGas is BaseFeeMultiplier * next base fee + MaxPriorityFeePerGas
+}
+
+func DefaultGasOracleConfig() GasOracleConfig {
+	return GasOracleConfig{
+		Blocks:             20,
+		SlowPercentile:     25,
+		StandardPercentile: 50,
+		FastPercentile:     90,
+		BaseFeeMultiplier:  2,
+	}
+}
+
+// GasOracle suggests gas prices from the prices paid by the transactions of the latest blocks.
+//
+// On post-london nodes which support eth_feeHistory, the priority fees are the medians of the per block reward
+// percentiles over the sampled blocks, skipping empty blocks, or eth_maxPriorityFeePerGas if all were empty.
+// Legacy gas prices are the next base fee plus the priority fee.
+//
+// Otherwise the blocks are fetched with their transactions. Legacy gas prices are percentiles of the effective
+// gas prices, priority fees percentiles of the effective tips above the base fee. Transactions which pay nothing
+// are ignored. The sampled blocks are cached, so a new block costs one request. If the sampled blocks contain
+// no transactions, eth_gasPrice is suggested for every speed.
+//
+// Either way the suggestions are computed once per block.
+type GasOracle struct {
+	client *Client
+	config GasOracleConfig
+
+	mu           sync.Mutex
+	samples      map[int64]*gasSample
+	prices       *GasPrices
+	noFeeHistory bool // eth_feeHistory failed or the chain has no base fee
+}
+
+// gasSample holds the effective prices paid in one block.
+type gasSample struct {
+	baseFee  *big.Int
+	gasUsed  *big.Int
+	gasLimit *big.Int
+	prices   []*big.Int
+	tips     []*big.Int
+}
+
+func NewGasOracle(client *Client, config GasOracleConfig) *GasOracle {
+	if config.Blocks <= 0 {
+		config.Blocks = DefaultGasOracleConfig().Blocks
+	}
+	if config.BaseFeeMultiplier <= 0 {
+		config.BaseFeeMultiplier = DefaultGasOracleConfig().BaseFeeMultiplier
+	}
+
+	return &GasOracle{
+		client:  client,
+		config:  config,
+		samples: make(map[int64]*gasSample),
+	}
+}
+
+// SuggestGasPrices returns the suggestions for the next block.
+func (oracle *GasOracle) SuggestGasPrices(ctx context.Context) (*GasPrices, error) {
+	head, err := oracle.client.Eth.BlockNumberContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oracle.mu.Lock()
+	defer oracle.mu.Unlock()
+
+	if oracle.prices != nil && oracle.prices.BlockNumber == head {
+		return oracle.prices, nil
+	}
+
+	var prices *GasPrices
+	if !oracle.noFeeHistory {
+		prices, err = oracle.fromFeeHistory(ctx, head)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if prices == nil {
+		if err := oracle.sample(ctx, head); err != nil {
+			return nil, err
+		}
+
+		prices, err = oracle.compute(ctx, head)
+		if err != nil {
+			return nil, err
+		}
+	}
+	oracle.prices = prices
+
+	return prices, nil
+}
+
+// IsMethodNotFoundError reports whether the node does not support the requested method.
+func IsMethodNotFoundError(err error) bool {
+	if rpcError, ok := err.(*RPCError); ok && rpcError.Code == -32601 {
+		return true
+	}
+
+	return rpcErrorContains(err, []string{"method not found", "does not exist"})
+}
+
+// fromFeeHistory computes the suggestions from eth_feeHistory. It returns nil without error if the node does not support
+// the method or the chain has no base fee, and the oracle falls back to sampling blocks from then on.
+func (oracle *GasOracle) fromFeeHistory(ctx context.Context, head int64) (*GasPrices, error) {
+	percentiles := []float64{oracle.config.SlowPercentile, oracle.config.StandardPercentile, oracle.config.FastPercentile}
+
+	sorted := append([]float64{}, percentiles...)
+	sort.Float64s(sorted)
+
+	history, err := oracle.client.Eth.FeeHistoryContext(ctx, int64(oracle.config.Blocks), rpctypes.QuantityBlock(head), sorted)
+	if IsMethodNotFoundError(err) {
+		oracle.noFeeHistory = true
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history.BaseFeePerGas) == 0 || history.BaseFeePerGas[len(history.BaseFeePerGas)-1].Sign() == 0 {
+		oracle.noFeeHistory = true
+		return nil, nil
+	}
+
+	result := &GasPrices{BlockNumber: head, BaseFee: history.BaseFeePerGas[len(history.BaseFeePerGas)-1]}
+
+	var fallback *big.Int
+	suggest := func(p float64) (GasPriceSuggestion, error) {
+		column := sort.SearchFloat64s(sorted, p)
+
+		var tips []*big.Int
+		for block, rewards := range history.Reward {
+			if block < len(history.GasUsedRatio) && history.GasUsedRatio[block] == 0 || column >= len(rewards) {
+				continue
+			}
+			tips = append(tips, rewards[column])
+		}
+		sortBigInts(tips)
+
+		tip := percentile(tips, 50)
+		if tip == nil {
+			if fallback == nil {
+				suggested, err := oracle.client.Eth.MaxPriorityFeePerGasContext(ctx)
+				if err != nil {
+					return GasPriceSuggestion{}, err
+				}
+				fallback = suggested.BigInt()
+			}
+			tip = new(big.Int).Set(fallback)
+		}
+
+		maxFee := new(big.Int).Mul(result.BaseFee, big.NewInt(oracle.config.BaseFeeMultiplier))
+
+		return GasPriceSuggestion{
+			GasPrice:             new(big.Int).Add(result.BaseFee, tip),
+			MaxPriorityFeePerGas: tip,
+			MaxFeePerGas:         maxFee.Add(maxFee, tip),
+		}, nil
+	}
+
+	suggestions := make([]GasPriceSuggestion, len(percentiles))
+	for k, p := range percentiles {
+		if suggestions[k], err = suggest(p); err != nil {
+			return nil, err
+		}
+	}
+	result.Slow, result.Standard, result.Fast = suggestions[0], suggestions[1], suggestions[2]
+
+	return result, nil
+}
+
+// sample fetches the blocks of the sample window which are not cached yet and drops the ones which left it.
+func (oracle *GasOracle) sample(ctx context.Context, head int64) error {
+	oldest := head - int64(oracle.config.Blocks) + 1
+	if oldest < 0 {
+		oldest = 0
+	}
+
+	for number := range oracle.samples {
+		if number < oldest || number > head {
+			delete(oracle.samples, number)
+		}
+	}
+
+	batch := oracle.client.NewBatch()
+	futures := make(map[int64]BlockFuture)
+	for number := oldest; number <= head; number++ {
+		if _, ok := oracle.samples[number]; !ok {
+			futures[number] = batch.GetBlockByNumber(number, true)
+		}
+	}
+
+	if len(futures) == 0 {
+		return nil
+	}
+
+	if err := batch.ExecuteContext(ctx); err != nil {
+		return err
+	}
+
+	for number, future := range futures {
+		block, err := future.Get()
+		if err != nil {
+			return err
+		}
+		oracle.samples[number] = newGasSample(block)
+	}
+
+	return nil
+}
+
+func newGasSample(block *rpctypes.EtherBlock) *gasSample {
+	sample := &gasSample{
+		baseFee:  block.BaseFeePerGas,
+		gasUsed:  &block.GasUsed,
+		gasLimit: &block.GasLimit,
+	}
+
+	for _, tx := range block.TransactionsFull {
+		price, tip := effectiveGasPrice(&tx, block.BaseFeePerGas)
+		if price.Sign() <= 0 {
+			continue
+		}
+		sample.prices = append(sample.prices, price)
+		if tip != nil {
+			sample.tips = append(sample.tips, tip)
+		}
+	}
+
+	return sample
+}
+
+// effectiveGasPrice returns the price per gas the transaction paid and the tip above the base fee (nil before london).
+func effectiveGasPrice(tx *rpctypes.EtherTransaction, baseFee *big.Int) (*big.Int, *big.Int) {
+	if baseFee == nil {
+		return new(big.Int).Set(tx.GasPrice.BigInt()), nil
+	}
+
+	var tip *big.Int
+	if tx.Type == rpctypes.TransactionTypeDynamicFee {
+		tip = new(big.Int).Sub(tx.MaxFeePerGas.BigInt(), baseFee)
+		if tx.MaxPriorityFeePerGas.BigInt().Cmp(tip) < 0 {
+			tip.Set(tx.MaxPriorityFeePerGas.BigInt())
+		}
+	} else {
+		tip = new(big.Int).Sub(tx.GasPrice.BigInt(), baseFee)
+	}
+
+	if tip.Sign() < 0 {
+		tip.SetInt64(0)
+	}
+
+	return new(big.Int).Add(baseFee, tip), tip
+}
+
+func (oracle *GasOracle) compute(ctx context.Context, head int64) (*GasPrices, error) {
+	latest, ok := oracle.samples[head]
+	if !ok {
+		return nil, errors.New("latest block missing in gas price sample")
+	}
+
+	var prices, tips []*big.Int
+	for _, sample := range oracle.samples {
+		prices = append(prices, sample.prices...)
+		tips = append(tips, sample.tips...)
+	}
+
+	result := &GasPrices{BlockNumber: head}
+	if latest.baseFee != nil {
+		result.BaseFee = nextBaseFee(latest.baseFee, latest.gasUsed, latest.gasLimit)
+	}
+
+	if len(prices) == 0 {
+		gasPrice, err := oracle.client.Eth.GasPriceContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		prices = []*big.Int{gasPrice.BigInt()}
+		if result.BaseFee != nil {
+			tip := new(big.Int).Sub(gasPrice.BigInt(), result.BaseFee)
+			if tip.Sign() < 0 {
+				tip.SetInt64(0)
+			}
+			tips = []*big.Int{tip}
+		}
+	}
+
+	sortBigInts(prices)
+	sortBigInts(tips)
+
+	suggest := func(p float64) GasPriceSuggestion {
+		suggestion := GasPriceSuggestion{GasPrice: percentile(prices, p)}
+		if result.BaseFee != nil {
+			suggestion.MaxPriorityFeePerGas = percentile(tips, p)
+			if suggestion.MaxPriorityFeePerGas == nil {
+				suggestion.MaxPriorityFeePerGas = new(big.Int)
+			}
+			suggestion.MaxFeePerGas = new(big.Int).Mul(result.BaseFee, big.NewInt(oracle.config.BaseFeeMultiplier))
+			suggestion.MaxFeePerGas.Add(suggestion.MaxFeePerGas, suggestion.MaxPriorityFeePerGas)
+		}
+		return suggestion
+	}
+
+	result.Slow = suggest(oracle.config.SlowPercentile)
+	result.Standard = suggest(oracle.config.StandardPercentile)
+	result.Fast = suggest(oracle.config.FastPercentile)
+
+	return result, nil
+}
+
+// nextBaseFee computes the base fee of the child block as specified by EIP-1559.
+func nextBaseFee(baseFee, gasUsed, gasLimit *big.Int) *big.Int {
+	target := new(big.Int).Div(gasLimit, big.NewInt(2))
+	if target.Sign() == 0 || gasUsed.Cmp(target) == 0 {
+		return new(big.Int).Set(baseFee)
+	}
+
+	delta := new(big.Int)
+	if gasUsed.Cmp(target) > 0 {
+		delta.Sub(gasUsed, target)
+	} else {
+		delta.Sub(target, gasUsed)
+	}
+	delta.Mul(delta, baseFee)
+	delta.Div(delta, target)
+	delta.Div(delta, big.NewInt(8))
+
+	if gasUsed.Cmp(target) > 0 {
+		if delta.Sign() == 0 {
+			delta.SetInt64(1)
+		}
+		return delta.Add(baseFee, delta)
+	}
+
+	return delta.Sub(baseFee, delta)
+}
+
+// percentile returns the nearest rank percentile of sorted values, nil if there are none.
+func percentile(sorted []*big.Int, p float64) *big.Int {
+	if len(sorted) == 0 {
+		return nil
+	}
+
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+
+	return new(big.Int).Set(sorted[rank])
+}
+
+func sortBigInts(values []*big.Int) {
+	sort.Slice(values, func(i, j int) bool { return values[i].Cmp(values[j]) < 0 })
+}
+
+func copyBigInt(value *big.Int) *big.Int {
+	if value == nil {
+		return nil
+	}
+
+	return new(big.Int).Set(value)
+}
@@ -0,0 +1,298 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	testBaseFee = 1000
+
+	testFullBlock = `{"number":"%#x","hash":"0x%064x","parentHash":"0x%064x","nonce":"0x0000000000000000",` +
+		`"sha3Uncles":"0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347","logsBloom":"0x00",` +
+		`"transactionsRoot":"0x00","stateRoot":"0x00","receiptsRoot":"0x00","miner":"0x0000000000000000000000000000000000000000",` +
+		`"difficulty":"0x1","totalDifficulty":"0x1","size":"0x1","extraData":"0x","gasLimit":"0x2000","gasUsed":"0x1000",` +
+		`"timestamp":"0x5b4f5c2c","baseFeePerGas":"%#x","uncles":[],"transactions":[%v]}`
+
+	testLegacyTx = `{"hash":"0x%064x","blockHash":"0x00","blockNumber":"%#x","gas":"0x5208","gasPrice":"%#x",` +
+		`"from":"0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f","to":"0x3535353535353535353535353535353535353535",` +
+		`"nonce":"0x0","input":"0x","transactionIndex":"0x0","value":"0x0","v":"0x1b","r":"0x1","s":"0x1"}`
+
+	testDynamicFeeTx = `{"hash":"0x%064x","blockHash":"0x00","blockNumber":"%#x","gas":"0x5208","gasPrice":"%#x",` +
+		`"from":"0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f","to":"0x3535353535353535353535353535353535353535",` +
+		`"nonce":"0x1","input":"0x","transactionIndex":"0x1","value":"0x0","v":"0x0","r":"0x1","s":"0x1",` +
+		`"type":"0x2","chainId":"0x1","maxFeePerGas":"%#x","maxPriorityFeePerGas":"%#x","accessList":[],"yParity":"0x0"}`
+)
+
+// testGasBlock returns block n with base fee 1000, a legacy transaction tipping n + 1 and a dynamic fee transaction
+// tipping n + 5, so that blocks 0 - 3 contain the tips 1 - 8.
+func testGasBlock(n int64) json.RawMessage {
+	legacy := fmt.Sprintf(testLegacyTx, 2*n+1, n, testBaseFee+n+1)
+	dynamic := fmt.Sprintf(testDynamicFeeTx, 2*n+2, n, testBaseFee+n+5, 100000, n+5)
+	return json.RawMessage(fmt.Sprintf(testFullBlock, n, n+1, n, testBaseFee, legacy+","+dynamic))
+}
+
+// gasOracleTestServer answers eth_blockNumber with head, eth_getBlockByNumber with testGasBlock
+// and counts the requested blocks. Transactions are accepted. eth_feeHistory is answered with feeHistory if set
+// (an *RPCError is returned as error), otherwise like every unknown method with a method not found error.
+func gasOracleTestServer(head *int64, feeHistory interface{}) (*httptest.Server, *int32) {
+	blockCalls := new(int32)
+
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		response := RPCResponse{}
+		switch request.Method {
+		case MethodEthBlockNumber:
+			response.Result = fmt.Sprintf("%#x", atomic.LoadInt64(head))
+		case MethodGasPrice:
+			response.Result = "0x3e8"
+		case MethodGetTransactionCount:
+			response.Result = "0x0"
+		case MethodSendRawTransaction:
+			response.Result = "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"
+		case MethodGetBlockByNumber:
+			atomic.AddInt32(blockCalls, 1)
+			number, _ := rpctypes.HexToBigInt(request.Params.([]interface{})[0].(string))
+			response.Result = testGasBlock(number.Int64())
+		case MethodMaxPriorityFeePerGas:
+			response.Result = "0x7"
+		case MethodFeeHistory:
+			if rpcError, ok := feeHistory.(*RPCError); ok {
+				response.Error = rpcError
+				break
+			}
+			if feeHistory != nil {
+				response.Result = feeHistory
+				break
+			}
+			fallthrough
+		default:
+			response.Error = &RPCError{Code: -32601, Message: fmt.Sprintf("the method %v does not exist/is not available", request.Method)}
+		}
+		return response
+	})
+
+	return server, blockCalls
+}
+
+func testGasOracleConfig() GasOracleConfig {
+	config := DefaultGasOracleConfig()
+	config.Blocks = 4
+	return config
+}
+
+func TestGasOracle_SuggestGasPrices(t *testing.T) {
+	head := int64(3)
+	server, _ := gasOracleTestServer(&head, nil)
+	defer server.Close()
+
+	prices, err := NewGasOracle(NewRPCClient(server.URL), testGasOracleConfig()).SuggestGasPrices(context.Background())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if prices.BlockNumber != 3 || prices.BaseFee.Int64() != testBaseFee {
+		t.Errorf("wrong block [Expected: %v, Actual: %v]", []int64{3, testBaseFee}, []interface{}{prices.BlockNumber, prices.BaseFee})
+		return
+	}
+
+	tests := []struct {
+		suggestion GasPriceSuggestion
+		tip        int64
+	}{
+		{prices.Slow, 2},
+		{prices.Standard, 4},
+		{prices.Fast, 8},
+	}
+
+	for _, test := range tests {
+		if test.suggestion.GasPrice.Int64() != testBaseFee+test.tip ||
+			test.suggestion.MaxPriorityFeePerGas.Int64() != test.tip ||
+			test.suggestion.MaxFeePerGas.Int64() != 2*testBaseFee+test.tip {
+			t.Errorf("wrong suggestion [Expected: %v, Actual: %v]",
+				[]int64{testBaseFee + test.tip, test.tip, 2*testBaseFee + test.tip},
+				[]*big.Int{test.suggestion.GasPrice, test.suggestion.MaxPriorityFeePerGas, test.suggestion.MaxFeePerGas})
+		}
+	}
+}
+
+func TestGasOracle_CachesBlocks(t *testing.T) {
+	head := int64(3)
+	server, blockCalls := gasOracleTestServer(&head, nil)
+	defer server.Close()
+
+	oracle := NewGasOracle(NewRPCClient(server.URL), testGasOracleConfig())
+
+	for i := 0; i < 2; i++ {
+		if _, err := oracle.SuggestGasPrices(context.Background()); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if *blockCalls != 4 {
+		t.Errorf("blocks requested again for the same head [Expected: %v, Actual: %v]", 4, *blockCalls)
+		return
+	}
+
+	atomic.StoreInt64(&head, 4)
+
+	prices, err := oracle.SuggestGasPrices(context.Background())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if *blockCalls != 5 || prices.BlockNumber != 4 {
+		t.Errorf("wrong blocks requested for the new head [Expected: %v, Actual: %v]", []int64{5, 4}, []interface{}{*blockCalls, prices.BlockNumber})
+	}
+}
+
+func TestGasOracle_FeeHistory(t *testing.T) {
+	head := int64(3)
+	history := json.RawMessage(`{"oldestBlock":"0x0","baseFeePerGas":["0x3e8","0x3e8","0x3e8","0x3e8","0x44c"],` +
+		`"gasUsedRatio":[0.5,0.4,0,0.6],"reward":[["0x1","0x2","0x3"],["0x3","0x4","0x9"],["0x0","0x0","0x0"],["0x2","0x6","0x5"]]}`)
+	server, blockCalls := gasOracleTestServer(&head, history)
+	defer server.Close()
+
+	prices, err := NewGasOracle(NewRPCClient(server.URL), testGasOracleConfig()).SuggestGasPrices(context.Background())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if *blockCalls != 0 || prices.BaseFee.Int64() != 1100 {
+		t.Errorf("wrong base fee [Expected: %v, Actual: %v]", 1100, prices.BaseFee)
+		return
+	}
+
+	// medians of the non empty blocks
+	tests := []struct {
+		suggestion GasPriceSuggestion
+		tip        int64
+	}{
+		{prices.Slow, 2},
+		{prices.Standard, 4},
+		{prices.Fast, 5},
+	}
+
+	for _, test := range tests {
+		if test.suggestion.GasPrice.Int64() != 1100+test.tip ||
+			test.suggestion.MaxPriorityFeePerGas.Int64() != test.tip ||
+			test.suggestion.MaxFeePerGas.Int64() != 2200+test.tip {
+			t.Errorf("wrong suggestion [Expected: %v, Actual: %v]",
+				[]int64{1100 + test.tip, test.tip, 2200 + test.tip},
+				[]*big.Int{test.suggestion.GasPrice, test.suggestion.MaxPriorityFeePerGas, test.suggestion.MaxFeePerGas})
+		}
+	}
+}
+
+func TestGasOracle_FeeHistoryEmptyBlocks(t *testing.T) {
+	head := int64(1)
+	history := json.RawMessage(`{"oldestBlock":"0x0","baseFeePerGas":["0x3e8","0x3e8","0x3e8"],` +
+		`"gasUsedRatio":[0,0],"reward":[["0x0","0x0","0x0"],["0x0","0x0","0x0"]]}`)
+	server, _ := gasOracleTestServer(&head, history)
+	defer server.Close()
+
+	prices, err := NewGasOracle(NewRPCClient(server.URL), testGasOracleConfig()).SuggestGasPrices(context.Background())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if prices.Standard.MaxPriorityFeePerGas.Int64() != 7 {
+		t.Errorf("eth_maxPriorityFeePerGas not used [Expected: %v, Actual: %v]", 7, prices.Standard.MaxPriorityFeePerGas)
+	}
+}
+
+func TestGasOracle_FeeHistoryTransientError(t *testing.T) {
+	head := int64(3)
+	server, blockCalls := gasOracleTestServer(&head, &RPCError{Code: -32005, Message: "rate limit exceeded"})
+	defer server.Close()
+
+	oracle := NewGasOracle(NewRPCClient(server.URL), testGasOracleConfig())
+
+	if _, err := oracle.SuggestGasPrices(context.Background()); err == nil {
+		t.Errorf("rate limit error swallowed [Expected: %v, Actual: %v]", "error", err)
+	}
+
+	if oracle.noFeeHistory || *blockCalls != 0 {
+		t.Errorf("fell back to sampling blocks [Expected: %v, Actual: %v]", 0, *blockCalls)
+	}
+}
+
+func TestNextBaseFee(t *testing.T) {
+	tests := []struct {
+		gasUsed  int64
+		expected int64
+	}{
+		{5000, 1000},
+		{10000, 1125},
+		{0, 875},
+		{5001, 1001},
+	}
+
+	for _, test := range tests {
+		fee := nextBaseFee(big.NewInt(1000), big.NewInt(test.gasUsed), big.NewInt(10000))
+		if fee.Int64() != test.expected {
+			t.Errorf("wrong base fee for gas used %v [Expected: %v, Actual: %v]", test.gasUsed, test.expected, fee)
+		}
+	}
+}
+
+func TestNonceManager_GasStrategy(t *testing.T) {
+	head := int64(3)
+	server, _ := gasOracleTestServer(&head, nil)
+	defer server.Close()
+
+	key, err := crypto.HexToECDSA("4646464646464646464646464646464646464646464646464646464646464646")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	client := NewRPCClient(server.URL)
+	nm := NewNonceManager(client)
+	nm.GasStrategy = NewGasOracle(client, testGasOracleConfig())
+	nm.GasSpeed = GasFast
+
+	tx := eip155Transaction()
+	tx.Type = rpctypes.TransactionTypeDynamicFee
+	tx.GasPrice = nil
+
+	if _, err := nm.Send(tx, key); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if tx.MaxPriorityFeePerGas.Int64() != 8 || tx.MaxFeePerGas.Int64() != 2*testBaseFee+8 {
+		t.Errorf("wrong gas prices [Expected: %v, Actual: %v]", []int64{8, 2*testBaseFee + 8}, []*big.Int{tx.MaxPriorityFeePerGas, tx.MaxFeePerGas})
+	}
+}
+
+func TestFeeHistoryParams(t *testing.T) {
+	var params interface{}
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		params = request.Params
+		return RPCResponse{Result: map[string]interface{}{"oldestBlock": "0x1", "baseFeePerGas": []string{"0x1", "0x1"}, "gasUsedRatio": []float64{0.5}}}
+	})
+	defer server.Close()
+
+	if _, err := NewRPCClient(server.URL).Eth.FeeHistory(16, rpctypes.QuantityLatest(), nil); err != nil {
+		t.Error(err)
+		return
+	}
+
+	js, _ := json.Marshal(params)
+	if expected := `["0x10","latest",[]]`; string(js) != expected {
+		t.Errorf("wrong params [Expected: %v, Actual: %v]", expected, string(js))
+	}
+}
@@ -0,0 +1,305 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+// LogRangeErrorMessages are parts of the error messages (in lower case) with which providers reject eth_getLogs
+// requests over too many blocks or with too many results. GetLogsPaged splits the block range on these errors.
+// Rate limit errors like "too many requests" must not match, splitting would only send more requests.
+var LogRangeErrorMessages = []string{
+	"query returned more than",
+	"response size exceeded",
+	"block range is too large",
+	"block range too large",
+	"exceed maximum block range",
+	"exceeds maximum block range",
+	"range too wide",
+}
+
+// GetLogsPagedConfig controls how GetLogsPaged splits the block range of the filter into windows.
+type GetLogsPagedConfig struct {
+	WindowSize    int64 // blocks per eth_getLogs request to start with
+	MinWindowSize int64 // a window of this size is not split anymore, the error is returned
+	MaxWindowSize int64 // windows never grow beyond this size
+	GrowBelow     int   // the window is doubled after a request returned fewer logs than this
+	Concurrency   int   // number of windows requested at the same time
+}
+
+func DefaultGetLogsPagedConfig() *GetLogsPagedConfig {
+	return &GetLogsPagedConfig{
+		WindowSize:    1000,
+		MinWindowSize: 1,
+		MaxWindowSize: 100000,
+		GrowBelow:     1000,
+		Concurrency:   4,
+	}
+}
+
+// IsLogRangeError reports whether the node rejected eth_getLogs because of the size of the block range or result.
+func IsLogRangeError(err error) bool {
+	switch e := err.(type) {
+	case *RPCError:
+		return rpcErrorContains(err, LogRangeErrorMessages)
+	case *HTTPError:
+		return e.StatusCode == 408 || e.StatusCode == 413 || e.StatusCode == 504
+	case net.Error:
+		return e.Timeout()
+	}
+
+	return false
+}
+
+// GetLogsPaged is like GetLogs for block ranges too large for a single eth_getLogs request. The range from FromBlock
+// to ToBlock (tags are resolved with eth_blockNumber, a missing bound means latest) is requested in windows which are
+// halved when the node reports too many results or a timeout and doubled while results are sparse. A nil config
+// uses DefaultGetLogsPagedConfig. The logs are returned ordered by block number and log index.
+func (eth Eth) GetLogsPaged(params *NewFilterParams, config *GetLogsPagedConfig) ([]rpctypes.EtherLog, error) {
+	return eth.GetLogsPagedContext(context.Background(), params, config)
+}
+
+// GetLogsPagedContext is like GetLogsPaged but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetLogsPagedContext(ctx context.Context, params *NewFilterParams, config *GetLogsPagedConfig) ([]rpctypes.EtherLog, error) {
+	result := make([]rpctypes.EtherLog, 0)
+
+	err := eth.GetLogsPagedFuncContext(ctx, params, config, func(logs []rpctypes.EtherLog) error {
+		result = append(result, logs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetLogsPagedFunc is like GetLogsPaged but streams the logs: callback is called for every window in block order with
+// its logs ordered by block number and log index. An error returned by callback stops the paging and is returned.
+func (eth Eth) GetLogsPagedFunc(params *NewFilterParams, config *GetLogsPagedConfig, callback func(logs []rpctypes.EtherLog) error) error {
+	return eth.GetLogsPagedFuncContext(context.Background(), params, config, callback)
+}
+
+// GetLogsPagedFuncContext is like GetLogsPagedFunc but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetLogsPagedFuncContext(ctx context.Context, params *NewFilterParams, config *GetLogsPagedConfig, callback func(logs []rpctypes.EtherLog) error) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	if config == nil {
+		config = DefaultGetLogsPagedConfig()
+	}
+
+	if params.BlockHash != "" {
+		logs, err := eth.GetLogsContext(ctx, params)
+		if err != nil {
+			return err
+		}
+		sortLogs(logs)
+		return callback(logs)
+	}
+
+	from, to, err := eth.logRange(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	pager := &logPager{eth: eth, params: *params, config: config, size: config.WindowSize}
+
+	return pager.run(ctx, from, to, callback)
+}
+
+// logRange resolves the block range of the filter to block numbers.
+func (eth Eth) logRange(ctx context.Context, params *NewFilterParams) (int64, int64, error) {
+	var head *int64
+
+	resolve := func(q *rpctypes.Quantity) (int64, error) {
+		switch {
+		case q != nil && q.Block > -1:
+			return q.Block, nil
+		case q != nil && q.Tag == rpctypes.QuantityEarliest().Tag:
+			return 0, nil
+		case head == nil:
+			number, err := eth.BlockNumberContext(ctx)
+			if err != nil {
+				return 0, err
+			}
+			head = &number
+		}
+		return *head, nil
+	}
+
+	from, err := resolve(params.FromBlock)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	to, err := resolve(params.ToBlock)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return from, to, nil
+}
+
+type logPager struct {
+	eth    Eth
+	params NewFilterParams
+	config *GetLogsPagedConfig
+
+	mu   sync.Mutex
+	size int64
+}
+
+type logWindow struct {
+	index int
+	logs  []rpctypes.EtherLog
+	err   error
+}
+
+// run requests the windows from..to with at most Concurrency windows in flight (or waiting to be passed to callback)
+// and passes them to callback in order.
+func (pager *logPager) run(ctx context.Context, from, to int64, callback func(logs []rpctypes.EtherLog) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	concurrency := pager.config.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make(chan logWindow)
+	done := make(map[int][]rpctypes.EtherLog)
+	next, dispatched, emitted := from, 0, 0
+
+	for next <= to || emitted < dispatched {
+		if next <= to && dispatched-emitted < concurrency {
+			end := next + pager.windowSize() - 1
+			if end > to || end < next {
+				end = to
+			}
+
+			go func(index int, from, to int64) {
+				logs, err := pager.fetch(ctx, from, to)
+				select {
+				case results <- logWindow{index: index, logs: logs, err: err}:
+				case <-ctx.Done():
+				}
+			}(dispatched, next, end)
+
+			dispatched++
+			next = end + 1
+			continue
+		}
+
+		var window logWindow
+		select {
+		case window = <-results:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if window.err != nil {
+			return contextError(ctx, window.err)
+		}
+		done[window.index] = window.logs
+
+		for logs, ok := done[emitted]; ok; logs, ok = done[emitted] {
+			delete(done, emitted)
+			emitted++
+
+			sortLogs(logs)
+			if err := callback(logs); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetch requests the logs of from..to and bisects the range as long as the node rejects it.
+func (pager *logPager) fetch(ctx context.Context, from, to int64) ([]rpctypes.EtherLog, error) {
+	params := pager.params
+	params.FromBlock = rpctypes.QuantityBlock(from)
+	params.ToBlock = rpctypes.QuantityBlock(to)
+
+	logs, err := pager.eth.GetLogsContext(ctx, &params)
+	if err == nil {
+		pager.grow(to-from+1, len(logs))
+		return logs, nil
+	}
+
+	if ctx.Err() != nil || !IsLogRangeError(err) || to-from+1 <= pager.config.MinWindowSize || from == to {
+		return nil, err
+	}
+
+	middle := from + (to-from)/2
+	pager.shrink(middle - from + 1)
+
+	left, err := pager.fetch(ctx, from, middle)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := pager.fetch(ctx, middle+1, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(left, right...), nil
+}
+
+func (pager *logPager) windowSize() int64 {
+	pager.mu.Lock()
+	defer pager.mu.Unlock()
+
+	if pager.size < 1 {
+		pager.size = 1
+	}
+
+	return pager.size
+}
+
+// grow doubles the window size after a full size window returned fewer than GrowBelow logs.
+func (pager *logPager) grow(span int64, count int) {
+	pager.mu.Lock()
+	defer pager.mu.Unlock()
+
+	if count >= pager.config.GrowBelow || span < pager.size {
+		return
+	}
+
+	pager.size *= 2
+	if pager.config.MaxWindowSize > 0 && pager.size > pager.config.MaxWindowSize {
+		pager.size = pager.config.MaxWindowSize
+	}
+}
+
+// shrink limits the window size to span, the size of a window which has to be requested after a rejected one.
+func (pager *logPager) shrink(span int64) {
+	pager.mu.Lock()
+	defer pager.mu.Unlock()
+
+	if span < pager.config.MinWindowSize {
+		span = pager.config.MinWindowSize
+	}
+
+	if span < pager.size {
+		pager.size = span
+	}
+}
+
+// sortLogs orders logs canonically by block number and log index.
+func sortLogs(logs []rpctypes.EtherLog) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].LogIndex < logs[j].LogIndex
+	})
+}
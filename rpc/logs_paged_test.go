@@ -0,0 +1,164 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+// pagedLogServer serves blocks 0 to 99, block n has n % 3 logs. Requests with more than maxResults logs are rejected.
+func pagedLogServer(maxResults int, requests, inFlight, maxInFlight *int32) *httptest.Server {
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		response := RPCResponse{}
+
+		switch request.Method {
+		case MethodEthBlockNumber:
+			response.Result = "0x63"
+		case MethodGetLogs:
+			atomic.AddInt32(requests, 1)
+			current := atomic.AddInt32(inFlight, 1)
+			defer atomic.AddInt32(inFlight, -1)
+			for max := atomic.LoadInt32(maxInFlight); current > max && !atomic.CompareAndSwapInt32(maxInFlight, max, current); max = atomic.LoadInt32(maxInFlight) {
+			}
+			time.Sleep(time.Millisecond)
+
+			filter := request.Params.([]interface{})[0].(map[string]interface{})
+			from, _ := rpctypes.HexToBigInt(filter["fromBlock"].(string))
+			to, _ := rpctypes.HexToBigInt(filter["toBlock"].(string))
+
+			logs := make([]rpctypes.EtherLogRaw, 0)
+			for block := to.Int64(); block >= from.Int64(); block-- {
+				for index := block%3 - 1; index >= 0; index-- {
+					logs = append(logs, rpctypes.EtherLogRaw{
+						Address:          "0x7f0d15c7faae65896648c8273b6d7e43f58fa842",
+						Topics:           []string{},
+						Data:             "0x",
+						BlockNumber:      fmt.Sprintf("0x%x", block),
+						BlockHash:        "0x01",
+						TransactionHash:  "0x01",
+						TransactionIndex: "0x0",
+						LogIndex:         fmt.Sprintf("0x%x", index),
+					})
+				}
+			}
+
+			if len(logs) > maxResults {
+				response.Error = &RPCError{Code: -32005, Message: fmt.Sprintf("query returned more than %v results", maxResults)}
+			} else {
+				response.Result = logs
+			}
+		}
+
+		return response
+	})
+
+	return server
+}
+
+func TestEth_GetLogsPaged(t *testing.T) {
+	requests, inFlight, maxInFlight := new(int32), new(int32), new(int32)
+	server := pagedLogServer(10, requests, inFlight, maxInFlight)
+	defer server.Close()
+
+	config := &GetLogsPagedConfig{WindowSize: 40, MinWindowSize: 1, MaxWindowSize: 64, GrowBelow: 4, Concurrency: 3}
+	params := &NewFilterParams{FromBlock: rpctypes.QuantityBlock(0), ToBlock: rpctypes.QuantityLatest()}
+
+	logs, err := NewRPCClient(server.URL).Eth.GetLogsPaged(params, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// 33 blocks with 1 and 33 blocks with 2 logs
+	if len(logs) != 99 {
+		t.Errorf("wrong number of logs [Expected: %v, Actual: %v]", 99, len(logs))
+		return
+	}
+
+	for k := 1; k < len(logs); k++ {
+		previous, current := logs[k-1], logs[k]
+		if current.BlockNumber < previous.BlockNumber || (current.BlockNumber == previous.BlockNumber && current.LogIndex != previous.LogIndex+1) {
+			t.Errorf("logs not in order at %v [Expected: %v, Actual: %v]", k, "ascending block number and log index", fmt.Sprintf("%v/%v after %v/%v", current.BlockNumber, current.LogIndex, previous.BlockNumber, previous.LogIndex))
+			return
+		}
+	}
+
+	if atomic.LoadInt32(maxInFlight) > 3 {
+		t.Errorf("too many concurrent requests [Expected: %v, Actual: %v]", 3, atomic.LoadInt32(maxInFlight))
+	}
+}
+
+func TestEth_GetLogsPagedFuncStops(t *testing.T) {
+	requests, inFlight, maxInFlight := new(int32), new(int32), new(int32)
+	server := pagedLogServer(1000, requests, inFlight, maxInFlight)
+	defer server.Close()
+
+	config := &GetLogsPagedConfig{WindowSize: 10, MinWindowSize: 1, MaxWindowSize: 10, Concurrency: 2}
+	params := &NewFilterParams{FromBlock: rpctypes.QuantityBlock(0), ToBlock: rpctypes.QuantityBlock(99)}
+	stop := errors.New("stop")
+
+	windows := make([]int64, 0)
+	err := NewRPCClient(server.URL).Eth.GetLogsPagedFunc(params, config, func(logs []rpctypes.EtherLog) error {
+		windows = append(windows, logs[0].BlockNumber)
+		if len(windows) == 3 {
+			return stop
+		}
+		return nil
+	})
+
+	if err != stop {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", stop, err)
+	}
+
+	if len(windows) != 3 || windows[0] != 1 || windows[1] != 10 || windows[2] != 20 {
+		t.Errorf("wrong windows [Expected: %v, Actual: %v]", []int64{1, 10, 20}, windows)
+	}
+
+	if atomic.LoadInt32(requests) > 5 {
+		t.Errorf("requests after stop [Expected: at most %v, Actual: %v]", 5, atomic.LoadInt32(requests))
+	}
+}
+
+func TestEth_GetLogsPagedMinWindow(t *testing.T) {
+	requests, inFlight, maxInFlight := new(int32), new(int32), new(int32)
+	server := pagedLogServer(1, requests, inFlight, maxInFlight)
+	defer server.Close()
+
+	config := &GetLogsPagedConfig{WindowSize: 8, MinWindowSize: 1, Concurrency: 1}
+	params := &NewFilterParams{FromBlock: rpctypes.QuantityBlock(0), ToBlock: rpctypes.QuantityBlock(7)}
+
+	// block 2 alone has 2 logs, more than the node returns
+	if _, err := NewRPCClient(server.URL).Eth.GetLogsPaged(params, config); !IsLogRangeError(err) {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", "query returned more than 1 results", err)
+	}
+}
+
+func TestIsLogRangeError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{&RPCError{Code: -32005, Message: "query returned more than 10000 results"}, true},
+		{&RPCError{Code: -32000, Message: "exceed maximum block range: 5000"}, true},
+		{&RPCError{Code: -32602, Message: "Block range is too large"}, true},
+		{&RPCError{Code: -32602, Message: "invalid block range params"}, false},
+		{&RPCError{Code: -32000, Message: "block range extends beyond current head block"}, false},
+		{&RPCError{Code: -32005, Message: "Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range"}, true},
+		{&RPCError{Code: -32005, Message: "rate limit exceeded"}, false},
+		{&RPCError{Code: -32005, Message: "limit exceeded"}, false},
+		{&RPCError{Code: 429, Message: "Too Many Requests"}, false},
+		{&HTTPError{StatusCode: 504}, true},
+		{&HTTPError{StatusCode: 400}, false},
+	}
+
+	for _, test := range tests {
+		if IsLogRangeError(test.err) != test.expected {
+			t.Errorf("wrong classification of %v [Expected: %v, Actual: %v]", test.err, test.expected, !test.expected)
+		}
+	}
+}
@@ -1,7 +1,12 @@
 package rpc
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"math/big"
 )
 
 const (
@@ -9,8 +14,9 @@ const (
 	MethodEthBlockNumber                      = "eth_blockNumber"
 	MethodEthCall                             = "eth_call"
 	MethodCoinbase                            = "eth_coinbase"
-	MethodGas                                 = "eth_estimate_gas"
+	MethodGas                                 = "eth_estimateGas"
 	MethodGasPrice                            = "eth_gasPrice"
+	MethodFeeHistory                          = "eth_feeHistory"
 	MethodGetBalance                          = "eth_getBalance"
 	MethodGetBlockByHash                      = "eth_getBlockByHash"
 	MethodGetBlockByNumber                    = "eth_getBlockByNumber"
@@ -20,6 +26,7 @@ const (
 	MethodGetFilterChanges                    = "eth_getFilterChanges"
 	MethodGetFilterLogs                       = "eth_getFilterLogs"
 	MethodGetLogs                             = "eth_getLogs"
+	MethodGetProof                            = "eth_getProof"
 	MethodGetStorageAt                        = "eth_getStorageAt"
 	MethodGetTransactionByBlockNumberAndIndex = "eth_getTransactionByBlockNumberAndIndex"
 	MethodGetTransactionByBlockHashAndIndex   = "eth_getTransactionByBlockHashAndIndex"
@@ -32,6 +39,7 @@ const (
 	MethodGetUncleCountByBlockNumber          = "eth_getUncleCountByBlockNumber"
 	MethodGetWork                             = "eth_getWork"
 	MethodHashrate                            = "eth_hashrate"
+	MethodMaxPriorityFeePerGas                = "eth_maxPriorityFeePerGas"
 	MethodMining                              = "eth_mining"
 	MethodNewBlockFilter                      = "eth_newBlockFilter"
 	MethodNewFilter                           = "eth_newFilter"
@@ -43,8 +51,10 @@ const (
 	MethodSignTransaction                     = "eth_signTransaction"
 	MethodSubmitHashrate                      = "eth_submitHashrate"
 	MethodSubmitWork                          = "eth_submitWork"
+	MethodSubscribe                           = "eth_subscribe"
 	MethodSyncing                             = "eth_syncing"
 	MethodUninstallFilter                     = "eth_uninstallFilter"
+	MethodUnsubscribe                         = "eth_unsubscribe"
 )
 
 type Eth struct {
@@ -56,7 +66,12 @@ type Eth struct {
 	returns []string, a list of addresses owned by eth.client.
  */
 func (eth Eth) Accounts() ([]string, error) {
-	response, err := checkRPCError(eth.client.Call(MethodEthAccounts))
+	return eth.AccountsContext(context.Background())
+}
+
+// AccountsContext is like Accounts but takes a context.Context for deadlines and cancellation.
+func (eth Eth) AccountsContext(ctx context.Context) ([]string, error) {
+	response, err := checkRPCError(eth.client.CallContext(ctx, MethodEthAccounts))
 
 	if err != nil {
 		return nil, err
@@ -76,7 +91,12 @@ func (eth Eth) Accounts() ([]string, error) {
 	https://mainnet.infura.io/CnUcPjgWQg4BW4Ozxznl
  */
 func (eth Eth) BlockNumber() (int64, error) {
-	return eth.client.RequestInt64(MethodEthBlockNumber)
+	return eth.BlockNumberContext(context.Background())
+}
+
+// BlockNumberContext is like BlockNumber but takes a context.Context for deadlines and cancellation.
+func (eth Eth) BlockNumberContext(ctx context.Context) (int64, error) {
+	return eth.client.RequestInt64Context(ctx, MethodEthBlockNumber)
 }
 
 /*
@@ -87,11 +107,46 @@ func (eth Eth) BlockNumber() (int64, error) {
 
 */
 func (eth Eth) Call(callParams *EthCallParams, quantity *rpctypes.Quantity) (*rpctypes.HexString, error) {
+	return eth.CallContext(context.Background(), callParams, quantity)
+}
+
+// CallContext is like Call but takes a context.Context for deadlines and cancellation.
+func (eth Eth) CallContext(ctx context.Context, callParams *EthCallParams, quantity *rpctypes.Quantity) (*rpctypes.HexString, error) {
 	if quantity == nil {
 		quantity = rpctypes.QuantityLatest()
 	}
 
-	return eth.client.RequestHexString(MethodEthCall, callParams.ToMap(), quantity.HexStringOrTag())
+	return eth.CallAtContext(ctx, callParams, BlockByQuantity(quantity), nil)
+}
+
+/*
+	rpc method: "eth_call"
+	Like Call, but selects the block by number, tag or hash (EIP-1898) and applies the state overrides during the call.
+	If the call reverts, a *RevertError with the decoded revert reason is returned.
+
+	curl --data '{"method":"eth_call","params":[{"to":"0xd780ae2bf04cd96e577d3d014762f831d97129d0","data":"0x115976c4"},{"blockHash":"0x..."},{"0xd780ae2bf04cd96e577d3d014762f831d97129d0":{"balance":"0x1"}}],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
+*/
+func (eth Eth) CallAt(callParams *EthCallParams, block *BlockNumberOrHash, overrides StateOverrides) (*rpctypes.HexString, error) {
+	return eth.CallAtContext(context.Background(), callParams, block, overrides)
+}
+
+// CallAtContext is like CallAt but takes a context.Context for deadlines and cancellation.
+func (eth Eth) CallAtContext(ctx context.Context, callParams *EthCallParams, block *BlockNumberOrHash, overrides StateOverrides) (*rpctypes.HexString, error) {
+	if callParams == nil {
+		return nil, errors.New("params cannot be nil")
+	}
+
+	params := []interface{}{callParams.ToMap(), block.Param()}
+	if len(overrides) != 0 {
+		params = append(params, overrides.ToMap())
+	}
+
+	result, err := eth.client.RequestHexStringContext(ctx, MethodEthCall, params...)
+	if err != nil {
+		return nil, asRevertError(err)
+	}
+
+	return result, nil
 }
 
 /*
@@ -101,16 +156,37 @@ func (eth Eth) Call(callParams *EthCallParams, quantity *rpctypes.Quantity) (*rp
 	curl --data '{"method":"eth_coinbase","params":[],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
 func (eth Eth) Coinbase() (*rpctypes.HexString, error) {
-	return eth.client.RequestHexString(MethodCoinbase)
+	return eth.CoinbaseContext(context.Background())
+}
+
+// CoinbaseContext is like Coinbase but takes a context.Context for deadlines and cancellation.
+func (eth Eth) CoinbaseContext(ctx context.Context) (*rpctypes.HexString, error) {
+	return eth.client.RequestHexStringContext(ctx, MethodCoinbase)
 }
 
 /*
 	rpc method: "eth_estimateGas"
 	Makes a call or transaction, which won’t be added to the blockchain and returns the used gas, which can be used for estimating the used gas.
-    TODO
+	If the execution reverts, a *RevertError with the decoded revert reason is returned.
+
+	curl --data '{"method":"eth_estimateGas","params":[{"from":"0x407d73d8a49eeb85d32cf465507dd71d507100c1","to":"0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b","value":"0x186a0"}],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
  */
-func (eth Eth) EstimateGas(params *EthEstimateGasParams) (int64, error) {
-	return -1, NotImplemented
+func (eth Eth) EstimateGas(params *EthEstimateGasParams) (*big.Int, error) {
+	return eth.EstimateGasContext(context.Background(), params)
+}
+
+// EstimateGasContext is like EstimateGas but takes a context.Context for deadlines and cancellation.
+func (eth Eth) EstimateGasContext(ctx context.Context, params *EthEstimateGasParams) (*big.Int, error) {
+	if params == nil {
+		return nil, errors.New("params cannot be nil")
+	}
+
+	gas, err := eth.client.RequestEtherValueContext(ctx, MethodGas, params.Params()...)
+	if err != nil {
+		return nil, asRevertError(err)
+	}
+
+	return gas.BigInt(), nil
 }
 
 /*
@@ -120,7 +196,64 @@ func (eth Eth) EstimateGas(params *EthEstimateGasParams) (int64, error) {
 	curl --data '{"method":"eth_gasPrice","params":[],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
  */
 func (eth Eth) GasPrice() (*rpctypes.EtherValue, error) {
-	return eth.client.RequestEtherValue(MethodGasPrice)
+	return eth.GasPriceContext(context.Background())
+}
+
+// GasPriceContext is like GasPrice but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GasPriceContext(ctx context.Context) (*rpctypes.EtherValue, error) {
+	return eth.client.RequestEtherValueContext(ctx, MethodGasPrice)
+}
+
+/*
+	rpc method: "eth_maxPriorityFeePerGas"
+	Returns the priority fee per gas in wei the node suggests for dynamic fee transactions (EIP-1559).
+
+	curl --data '{"method":"eth_maxPriorityFeePerGas","params":[],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
+ */
+func (eth Eth) MaxPriorityFeePerGas() (*rpctypes.EtherValue, error) {
+	return eth.MaxPriorityFeePerGasContext(context.Background())
+}
+
+// MaxPriorityFeePerGasContext is like MaxPriorityFeePerGas but takes a context.Context for deadlines and cancellation.
+func (eth Eth) MaxPriorityFeePerGasContext(ctx context.Context) (*rpctypes.EtherValue, error) {
+	return eth.client.RequestEtherValueContext(ctx, MethodMaxPriorityFeePerGas)
+}
+
+/*
+	rpc method: "eth_feeHistory"
+	Returns the base fees, gas used ratios and the priority fees at the given percentiles (0 - 100, ascending)
+	of blockCount blocks up to newestBlock.
+
+	curl --data '{"method":"eth_feeHistory","params":["0x4","latest",[25,75]],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
+ */
+func (eth Eth) FeeHistory(blockCount int64, newestBlock *rpctypes.Quantity, rewardPercentiles []float64) (*rpctypes.FeeHistory, error) {
+	return eth.FeeHistoryContext(context.Background(), blockCount, newestBlock, rewardPercentiles)
+}
+
+// FeeHistoryContext is like FeeHistory but takes a context.Context for deadlines and cancellation.
+func (eth Eth) FeeHistoryContext(ctx context.Context, blockCount int64, newestBlock *rpctypes.Quantity, rewardPercentiles []float64) (*rpctypes.FeeHistory, error) {
+	if newestBlock == nil {
+		newestBlock = rpctypes.QuantityLatest()
+	}
+	if rewardPercentiles == nil {
+		rewardPercentiles = []float64{}
+	}
+
+	response, err := checkRPCError(eth.client.CallContext(ctx, MethodFeeHistory, rpctypes.BigIntToHex(big.NewInt(blockCount)), newestBlock.HexStringOrTag(), rewardPercentiles))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("response returned without error but no fee history found for %v blocks up to %v", blockCount, newestBlock)
+	}
+
+	js, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(rpctypes.FeeHistoryRaw).FromJSON(js)
 }
 
 /*
@@ -129,7 +262,12 @@ func (eth Eth) GasPrice() (*rpctypes.EtherValue, error) {
 	curl --data '{"method":"eth_getBalance","params":["0x407d73d8a49eeb85d32cf465507dd71d507100c1"],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
 func (eth Eth) GetBalance(address string, quantity *rpctypes.Quantity) (*rpctypes.EtherValue, error) {
-	return eth.client.RequestEtherValue(MethodGetBalance, address, quantity.HexStringOrTag())
+	return eth.GetBalanceContext(context.Background(), address, quantity)
+}
+
+// GetBalanceContext is like GetBalance but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetBalanceContext(ctx context.Context, address string, quantity *rpctypes.Quantity) (*rpctypes.EtherValue, error) {
+	return eth.client.RequestEtherValueContext(ctx, MethodGetBalance, address, quantity.HexStringOrTag())
 }
 
 /*
@@ -137,7 +275,12 @@ func (eth Eth) GetBalance(address string, quantity *rpctypes.Quantity) (*rpctype
 	Returns information about a block by hash.
  */
 func (eth Eth) GetBlockByHash(address string, full bool) (*rpctypes.EtherBlock, error) {
-	return eth.client.RequestEtherBlock(MethodGetBlockByHash, address, full)
+	return eth.GetBlockByHashContext(context.Background(), address, full)
+}
+
+// GetBlockByHashContext is like GetBlockByHash but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetBlockByHashContext(ctx context.Context, address string, full bool) (*rpctypes.EtherBlock, error) {
+	return eth.client.RequestEtherBlockContext(ctx, MethodGetBlockByHash, address, full)
 }
 
 /*
@@ -145,7 +288,12 @@ func (eth Eth) GetBlockByHash(address string, full bool) (*rpctypes.EtherBlock,
 	Returns information about a block by block number.
  */
 func (eth Eth) GetBlockByNumber(blockNumber int64, full bool) (*rpctypes.EtherBlock, error) {
-	return eth.client.RequestEtherBlock(MethodGetBlockByNumber, new(rpctypes.HexString).FromInt64(blockNumber).String(), full)
+	return eth.GetBlockByNumberContext(context.Background(), blockNumber, full)
+}
+
+// GetBlockByNumberContext is like GetBlockByNumber but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetBlockByNumberContext(ctx context.Context, blockNumber int64, full bool) (*rpctypes.EtherBlock, error) {
+	return eth.client.RequestEtherBlockContext(ctx, MethodGetBlockByNumber, new(rpctypes.HexString).FromInt64(blockNumber).String(), full)
 }
 
 /*
@@ -153,7 +301,12 @@ func (eth Eth) GetBlockByNumber(blockNumber int64, full bool) (*rpctypes.EtherBl
 	Returns the number of transactions in a block from a block matching the given block hash.
  */
 func (eth Eth) GetBlockTransactionCountByHash(address string) (int64, error) {
-	return eth.client.RequestInt64(MethodGetBlockTransactionCountByHash, address)
+	return eth.GetBlockTransactionCountByHashContext(context.Background(), address)
+}
+
+// GetBlockTransactionCountByHashContext is like GetBlockTransactionCountByHash but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetBlockTransactionCountByHashContext(ctx context.Context, address string) (int64, error) {
+	return eth.client.RequestInt64Context(ctx, MethodGetBlockTransactionCountByHash, address)
 }
 
 /*
@@ -161,15 +314,102 @@ func (eth Eth) GetBlockTransactionCountByHash(address string) (int64, error) {
 	Returns the number of transactions in a block from a block matching the given block number.
  */
 func (eth Eth) GetBlockTransactionCountByNumber(blockNumber int64) (int64, error) {
-	return eth.client.RequestInt64(MethodGetBlockTransactionCountByNumber, new(rpctypes.HexString).FromInt64(blockNumber).String())
+	return eth.GetBlockTransactionCountByNumberContext(context.Background(), blockNumber)
+}
+
+// GetBlockTransactionCountByNumberContext is like GetBlockTransactionCountByNumber but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetBlockTransactionCountByNumberContext(ctx context.Context, blockNumber int64) (int64, error) {
+	return eth.client.RequestInt64Context(ctx, MethodGetBlockTransactionCountByNumber, new(rpctypes.HexString).FromInt64(blockNumber).String())
+}
+
+/*
+	rpc method: "eth_getCode"
+	Returns the code at the given address, empty for externally owned accounts.
+	curl --data '{"method":"eth_getCode","params":["0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b","latest"],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
+*/
+func (eth Eth) GetCode(address string, quantity *rpctypes.Quantity) (*rpctypes.HexString, error) {
+	return eth.GetCodeContext(context.Background(), address, quantity)
+}
+
+// GetCodeContext is like GetCode but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetCodeContext(ctx context.Context, address string, quantity *rpctypes.Quantity) (*rpctypes.HexString, error) {
+	if quantity == nil {
+		quantity = rpctypes.QuantityLatest()
+	}
+
+	return eth.client.RequestHexStringContext(ctx, MethodGetCode, address, quantity.HexStringOrTag())
+}
+
+// IsContract reports whether there is code at the address, i.e. whether it is a contract and not an externally owned account.
+func (eth Eth) IsContract(address string, quantity *rpctypes.Quantity) (bool, error) {
+	return eth.IsContractContext(context.Background(), address, quantity)
+}
+
+// IsContractContext is like IsContract but takes a context.Context for deadlines and cancellation.
+func (eth Eth) IsContractContext(ctx context.Context, address string, quantity *rpctypes.Quantity) (bool, error) {
+	code, err := eth.GetCodeContext(ctx, address, quantity)
+	if err != nil {
+		return false, err
+	}
+
+	return len(code.Bytes()) > 0, nil
 }
 
 /*
-	TODO
-	eth_getCode
-	eth_getFilterChanges
+	rpc method: "eth_getProof"
+	Returns the account and storage values of the given address with their merkle proofs (EIP-1186).
+	The proofs can be checked against a trusted state root with VerifyAccountProof.
+	curl --data '{"method":"eth_getProof","params":["0x7f0d15c7faae65896648c8273b6d7e43f58fa842",["0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421"],"latest"],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
+func (eth Eth) GetProof(address string, storageKeys []string, quantity *rpctypes.Quantity) (*rpctypes.AccountProof, error) {
+	return eth.GetProofContext(context.Background(), address, storageKeys, quantity)
+}
+
+// GetProofContext is like GetProof but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetProofContext(ctx context.Context, address string, storageKeys []string, quantity *rpctypes.Quantity) (*rpctypes.AccountProof, error) {
+	if quantity == nil {
+		quantity = rpctypes.QuantityLatest()
+	}
+	if storageKeys == nil {
+		storageKeys = []string{}
+	}
 
+	response, err := checkRPCError(eth.client.CallContext(ctx, MethodGetProof, address, storageKeys, quantity.HexStringOrTag()))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Result == nil {
+		return nil, fmt.Errorf("response returned without error but no proof found for %v", address)
+	}
+
+	js, err := json.Marshal(response.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(rpctypes.AccountProofRaw).FromJSON(js)
+}
+
+/*
+	rpc method: "eth_getFilterChanges"
+	Polling method for a filter, returns the changes since the last poll: logs for filters created with eth_newFilter,
+	block hashes for eth_newBlockFilter and transaction hashes for eth_newPendingTransactionFilter.
+	curl --data '{"method":"eth_getFilterChanges","params":["0x16"],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
+*/
+func (eth Eth) GetFilterChanges(filterID string) (*FilterChanges, error) {
+	return eth.GetFilterChangesContext(context.Background(), filterID)
+}
+
+// GetFilterChangesContext is like GetFilterChanges but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetFilterChangesContext(ctx context.Context, filterID string) (*FilterChanges, error) {
+	response, err := checkRPCError(eth.client.CallContext(ctx, MethodGetFilterChanges, filterID))
+	if err != nil {
+		return nil, err
+	}
+
+	return getFilterChangesFromResponse(response.Result)
+}
 
 /*
 	rpc method: "eth_getFilterLogs"
@@ -177,7 +417,12 @@ func (eth Eth) GetBlockTransactionCountByNumber(blockNumber int64) (int64, error
 	curl --data '{"method":"eth_getFilterLogs","params":["0x16"],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
 func (eth Eth) GetFilterLogs(filterID string) ([]rpctypes.EtherLog, error) {
-	return eth.client.RequestEtherLogList(MethodGetFilterLogs, filterID)
+	return eth.GetFilterLogsContext(context.Background(), filterID)
+}
+
+// GetFilterLogsContext is like GetFilterLogs but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetFilterLogsContext(ctx context.Context, filterID string) ([]rpctypes.EtherLog, error) {
+	return eth.client.RequestEtherLogListContext(ctx, MethodGetFilterLogs, filterID)
 }
 
 /*
@@ -186,16 +431,38 @@ func (eth Eth) GetFilterLogs(filterID string) ([]rpctypes.EtherLog, error) {
 	curl --data '{"method":"eth_getLogs","params":[{"topics":["0x000000000000000000000000a94f5374fce5edbc8e2a8697c15331677e6ebf0b"]}],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
 func (eth Eth) GetLogs(params *NewFilterParams) ([]rpctypes.EtherLog, error) {
-	return eth.client.RequestEtherLogList(MethodGetLogs, params.ToMap())
+	return eth.GetLogsContext(context.Background(), params)
+}
+
+// GetLogsContext is like GetLogs but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetLogsContext(ctx context.Context, params *NewFilterParams) ([]rpctypes.EtherLog, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return eth.client.RequestEtherLogListContext(ctx, MethodGetLogs, params.ToMap())
 }
 
 /*
 	rpc method: "eth_getStorageAt"
-	Returns the value from a storage position at a given address.
+	Returns the 32 byte word at a storage slot of the given address. The slot is a full 32 byte key,
+	package storage computes the slots of solidity state variables.
 	curl --data '{"method":"eth_getStorageAt","params":["0x407d73d8a49eeb85d32cf465507dd71d507100c1","0x0","0x2"],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
-func (eth Eth) GetStorageAt(contractAddress string, index int64, quantity *rpctypes.Quantity) (*rpctypes.HexString, error) {
-	return eth.client.RequestHexString(MethodGetStorageAt, contractAddress, new(rpctypes.HexString).FromInt64(index).Hash(), quantity.String())
+func (eth Eth) GetStorageAt(contractAddress string, slot *big.Int, quantity *rpctypes.Quantity) (*rpctypes.HexString, error) {
+	return eth.GetStorageAtContext(context.Background(), contractAddress, slot, quantity)
+}
+
+// GetStorageAtContext is like GetStorageAt but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetStorageAtContext(ctx context.Context, contractAddress string, slot *big.Int, quantity *rpctypes.Quantity) (*rpctypes.HexString, error) {
+	if slot == nil || slot.Sign() < 0 || slot.BitLen() > 256 {
+		return nil, fmt.Errorf("invalid storage slot %v", slot)
+	}
+	if quantity == nil {
+		quantity = rpctypes.QuantityLatest()
+	}
+
+	return eth.client.RequestHexStringContext(ctx, MethodGetStorageAt, contractAddress, fmt.Sprintf("0x%064x", slot), quantity.HexStringOrTag())
 }
 
 /*
@@ -203,7 +470,12 @@ func (eth Eth) GetStorageAt(contractAddress string, index int64, quantity *rpcty
 	Returns information about a transaction by block hash and transaction index position.
 */
 func (eth Eth) GetTransactionByBlockHashAndIndex(hash string, index int64) (*rpctypes.EtherTransaction, error) {
-	return eth.client.RequestEtherTransaction(MethodGetTransactionByBlockHashAndIndex, hash, new(rpctypes.HexString).FromInt64(index).String())
+	return eth.GetTransactionByBlockHashAndIndexContext(context.Background(), hash, index)
+}
+
+// GetTransactionByBlockHashAndIndexContext is like GetTransactionByBlockHashAndIndex but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetTransactionByBlockHashAndIndexContext(ctx context.Context, hash string, index int64) (*rpctypes.EtherTransaction, error) {
+	return eth.client.RequestEtherTransactionContext(ctx, MethodGetTransactionByBlockHashAndIndex, hash, new(rpctypes.HexString).FromInt64(index).String())
 }
 
 /*
@@ -211,7 +483,12 @@ func (eth Eth) GetTransactionByBlockHashAndIndex(hash string, index int64) (*rpc
 	Returns information about a transaction by block number and transaction index position.
 */
 func (eth Eth) GetTransactionByBlockNumberAndIndex(blockNumber int64, index int64) (*rpctypes.EtherTransaction, error) {
-	return eth.client.RequestEtherTransaction(MethodGetTransactionByBlockNumberAndIndex, new(rpctypes.HexString).FromInt64(blockNumber).String(), new(rpctypes.HexString).FromInt64(index).String())
+	return eth.GetTransactionByBlockNumberAndIndexContext(context.Background(), blockNumber, index)
+}
+
+// GetTransactionByBlockNumberAndIndexContext is like GetTransactionByBlockNumberAndIndex but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetTransactionByBlockNumberAndIndexContext(ctx context.Context, blockNumber int64, index int64) (*rpctypes.EtherTransaction, error) {
+	return eth.client.RequestEtherTransactionContext(ctx, MethodGetTransactionByBlockNumberAndIndex, new(rpctypes.HexString).FromInt64(blockNumber).String(), new(rpctypes.HexString).FromInt64(index).String())
 }
 
 /*
@@ -219,7 +496,12 @@ func (eth Eth) GetTransactionByBlockNumberAndIndex(blockNumber int64, index int6
 	Returns the information about a transaction requested by transaction hash.
 */
 func (eth Eth) GetTransactionByHash(hash string) (*rpctypes.EtherTransaction, error) {
-	return eth.client.RequestEtherTransaction(MethodGetTransactionByHash, hash)
+	return eth.GetTransactionByHashContext(context.Background(), hash)
+}
+
+// GetTransactionByHashContext is like GetTransactionByHash but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetTransactionByHashContext(ctx context.Context, hash string) (*rpctypes.EtherTransaction, error) {
+	return eth.client.RequestEtherTransactionContext(ctx, MethodGetTransactionByHash, hash)
 }
 
 /*
@@ -227,7 +509,12 @@ func (eth Eth) GetTransactionByHash(hash string) (*rpctypes.EtherTransaction, er
 	Returns the number of transactions sent from an address.
 */
 func (eth Eth) GetTransactionCount(hash string, quantity *rpctypes.Quantity) (int64, error) {
-	return eth.client.RequestInt64(MethodGetTransactionCount, hash, quantity.HexStringOrTag())
+	return eth.GetTransactionCountContext(context.Background(), hash, quantity)
+}
+
+// GetTransactionCountContext is like GetTransactionCount but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetTransactionCountContext(ctx context.Context, hash string, quantity *rpctypes.Quantity) (int64, error) {
+	return eth.client.RequestInt64Context(ctx, MethodGetTransactionCount, hash, quantity.HexStringOrTag())
 }
 
 /*
@@ -238,7 +525,12 @@ func (eth Eth) GetTransactionCount(hash string, quantity *rpctypes.Quantity) (in
 https://mainnet.infura.io/CnUcPjgWQg4BW4Ozxznl
 */
 func (eth Eth) GetTransactionReceipt(hash string) (*rpctypes.EtherTransactionReceipt, error) {
-	return eth.client.RequestEtherTransactionReceipt(MethodGetTransactionReceipt, hash)
+	return eth.GetTransactionReceiptContext(context.Background(), hash)
+}
+
+// GetTransactionReceiptContext is like GetTransactionReceipt but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetTransactionReceiptContext(ctx context.Context, hash string) (*rpctypes.EtherTransactionReceipt, error) {
+	return eth.client.RequestEtherTransactionReceiptContext(ctx, MethodGetTransactionReceipt, hash)
 }
 
 /*
@@ -255,7 +547,12 @@ func (eth Eth) GetTransactionReceipt(hash string) (*rpctypes.EtherTransactionRec
 	curl --data '{"method":"eth_hashrate","params":[],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
 func (eth Eth) Hashrate() (int64, error) {
-	return eth.client.RequestInt64(MethodHashrate)
+	return eth.HashrateContext(context.Background())
+}
+
+// HashrateContext is like Hashrate but takes a context.Context for deadlines and cancellation.
+func (eth Eth) HashrateContext(ctx context.Context) (int64, error) {
+	return eth.client.RequestInt64Context(ctx, MethodHashrate)
 }
 
 /*
@@ -265,40 +562,181 @@ func (eth Eth) Hashrate() (int64, error) {
 	curl --data '{"method":"eth_mining","params":[],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
 func (eth Eth) Mining() (bool, error) {
-	return eth.client.RequestBool(MethodMining)
+	return eth.MiningContext(context.Background())
+}
+
+// MiningContext is like Mining but takes a context.Context for deadlines and cancellation.
+func (eth Eth) MiningContext(ctx context.Context) (bool, error) {
+	return eth.client.RequestBoolContext(ctx, MethodMining)
 }
 
 /*
-TODO
-eth_newBlockFilter
+	rpc method: "eth_newBlockFilter"
+	Creates a filter in the node, to notify when a new block arrives. To check if the state has changed, call eth_getFilterChanges.
+	curl --data '{"method":"eth_newBlockFilter","params":[],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
+func (eth Eth) NewBlockFilter() (*rpctypes.HexString, error) {
+	return eth.NewBlockFilterContext(context.Background())
+}
+
+// NewBlockFilterContext is like NewBlockFilter but takes a context.Context for deadlines and cancellation.
+func (eth Eth) NewBlockFilterContext(ctx context.Context) (*rpctypes.HexString, error) {
+	return eth.client.RequestHexStringContext(ctx, MethodNewBlockFilter)
+}
 
 /*
 	rpc method: "eth_newFilter"
 	Creates a filter object, based on filter options, to notify when the state changes (logs). To check if the state has changed, call eth_getFilterChanges.
 */
 func (eth Eth) NewFilter(params *NewFilterParams) (*rpctypes.HexString, error) {
-	return eth.client.RequestHexString(MethodNewFilter, params.ToMap())
+	return eth.NewFilterContext(context.Background(), params)
+}
+
+// NewFilterContext is like NewFilter but takes a context.Context for deadlines and cancellation.
+func (eth Eth) NewFilterContext(ctx context.Context, params *NewFilterParams) (*rpctypes.HexString, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return eth.client.RequestHexStringContext(ctx, MethodNewFilter, params.ToMap())
 }
 
 /*
-TODO
-eth_newPendingTransactionFilter
+	rpc method: "eth_newPendingTransactionFilter"
+	Creates a filter in the node, to notify when new pending transactions arrive. To check if the state has changed, call eth_getFilterChanges.
+	curl --data '{"method":"eth_newPendingTransactionFilter","params":[],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
+func (eth Eth) NewPendingTransactionFilter() (*rpctypes.HexString, error) {
+	return eth.NewPendingTransactionFilterContext(context.Background())
+}
+
+// NewPendingTransactionFilterContext is like NewPendingTransactionFilter but takes a context.Context for deadlines and cancellation.
+func (eth Eth) NewPendingTransactionFilterContext(ctx context.Context) (*rpctypes.HexString, error) {
+	return eth.client.RequestHexStringContext(ctx, MethodNewPendingTransactionFilter)
+}
 
 /*
 	rpc method: "eth_protocolVersion"
 	Returns the current ethereum protocol version.
 */
 func (eth Eth) ProtocolVersion() (int64, error) {
-	return eth.client.RequestInt64(MethodProtocolVersion)
+	return eth.ProtocolVersionContext(context.Background())
+}
+
+// ProtocolVersionContext is like ProtocolVersion but takes a context.Context for deadlines and cancellation.
+func (eth Eth) ProtocolVersionContext(ctx context.Context) (int64, error) {
+	return eth.client.RequestInt64Context(ctx, MethodProtocolVersion)
+}
+
+/*
+	rpc method: "eth_sendRawTransaction"
+	Creates new message call transaction or a contract creation for signed transactions.
+	returns the transaction hash, or the zero hash if the transaction is not yet available.
+
+	curl --data '{"method":"eth_sendRawTransaction","params":["0xd46e8dd67c5d32be8d46e8dd67c5d32be8058bb8eb970870f072445675058bb8eb970870f072445675"],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
+*/
+func (eth Eth) SendRawTransaction(data string) (*rpctypes.HexString, error) {
+	return eth.SendRawTransactionContext(context.Background(), data)
+}
+
+// SendRawTransactionContext is like SendRawTransaction but takes a context.Context for deadlines and cancellation.
+func (eth Eth) SendRawTransactionContext(ctx context.Context, data string) (*rpctypes.HexString, error) {
+	return eth.client.RequestHexStringContext(ctx, MethodSendRawTransaction, data)
+}
+
+/*
+	rpc method: "eth_sendRawTransaction"
+	Submits a locally signed transaction, see RawTransaction.Sign.
+*/
+func (eth Eth) SendSignedTransaction(tx *SignedTransaction) (*rpctypes.HexString, error) {
+	return eth.SendSignedTransactionContext(context.Background(), tx)
+}
+
+// SendSignedTransactionContext is like SendSignedTransaction but takes a context.Context for deadlines and cancellation.
+func (eth Eth) SendSignedTransactionContext(ctx context.Context, tx *SignedTransaction) (*rpctypes.HexString, error) {
+	if tx == nil {
+		return nil, errors.New("transaction cannot be nil")
+	}
+
+	return eth.SendRawTransactionContext(ctx, tx.Raw.Hash())
+}
+
+/*
+	rpc method: "eth_sendTransaction"
+	Creates new message call transaction or a contract creation, signed by an account of the node.
+	returns the transaction hash.
+
+	curl --data '{"method":"eth_sendTransaction","params":[{"from":"0xb60e8dd61c5d32be8058bb8eb970870f07233155","to":"0xd46e8dd67c5d32be8058bb8eb970870f07244567","value":"0x9184e72a"}],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
+*/
+func (eth Eth) SendTransaction(sendTransactionParams *SendTransaction) (*rpctypes.HexString, error) {
+	return eth.SendTransactionContext(context.Background(), sendTransactionParams)
+}
+
+// SendTransactionContext is like SendTransaction but takes a context.Context for deadlines and cancellation.
+func (eth Eth) SendTransactionContext(ctx context.Context, sendTransactionParams *SendTransaction) (*rpctypes.HexString, error) {
+	if sendTransactionParams == nil {
+		return nil, errors.New("transaction cannot be nil")
+	}
+
+	return eth.client.RequestHexStringContext(ctx, MethodSendTransaction, sendTransactionParams.ToMap())
+}
+
+/*
+	rpc method: "eth_sign"
+	Signs keccak256("\x19Ethereum Signed Message:\n" + len(message) + message) with an account of the node.
+	returns the 65 byte signature.
+
+	curl --data '{"method":"eth_sign","params":["0x9b2055d370f73ec7d8a03e965129118dc8f5bf83","0xdeadbeaf"],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
+*/
+func (eth Eth) Sign(address string, data *rpctypes.HexString) (*rpctypes.HexString, error) {
+	return eth.SignContext(context.Background(), address, data)
+}
+
+// SignContext is like Sign but takes a context.Context for deadlines and cancellation.
+func (eth Eth) SignContext(ctx context.Context, address string, data *rpctypes.HexString) (*rpctypes.HexString, error) {
+	if data == nil {
+		return nil, errors.New("data to sign cannot be nil")
+	}
+
+	return eth.client.RequestHexStringContext(ctx, MethodSign, address, data.Hash())
+}
+
+/*
+	rpc method: "eth_signTransaction"
+	Signs a transaction with an account of the node without submitting it.
+	returns the rlp encoded signed transaction, which can be submitted with SendRawTransaction.
+
+	curl --data '{"method":"eth_signTransaction","params":[{"from":"0xb60e8dd61c5d32be8058bb8eb970870f07233155","to":"0xd46e8dd67c5d32be8058bb8eb970870f07244567","gas":"0x76c0","gasPrice":"0x9184e72a000","value":"0x9184e72a","nonce":"0x0"}],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
+*/
+func (eth Eth) SignTransaction(sendTransactionParams *SendTransaction) (*rpctypes.HexString, error) {
+	return eth.SignTransactionContext(context.Background(), sendTransactionParams)
+}
+
+// SignTransactionContext is like SignTransaction but takes a context.Context for deadlines and cancellation.
+func (eth Eth) SignTransactionContext(ctx context.Context, sendTransactionParams *SendTransaction) (*rpctypes.HexString, error) {
+	if sendTransactionParams == nil {
+		return nil, errors.New("transaction cannot be nil")
+	}
+
+	response, err := checkRPCError(eth.client.CallContext(ctx, MethodSignTransaction, sendTransactionParams.ToMap()))
+	if err != nil {
+		return nil, err
+	}
+
+	// geth and parity answer with {"raw": "0x...", "tx": {...}}, older nodes with the raw transaction only
+	switch result := response.Result.(type) {
+	case string:
+		return rpctypes.NewHexString(result)
+	case map[string]interface{}:
+		if raw, ok := result["raw"].(string); ok {
+			return rpctypes.NewHexString(raw)
+		}
+	}
+
+	return nil, fmt.Errorf("could not parse signed transaction from %v", response.Result)
 }
 
 /*
-	eth_sendRawTransaction
-	eth_sendTransaction
-	eth_sign
-	eth_signTransaction
 	eth_submitHashrate
 	eth_submitWork
 */
@@ -309,7 +747,12 @@ func (eth Eth) ProtocolVersion() (int64, error) {
 	curl --data '{"method":"eth_syncing","params":[],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
 func (eth Eth) Syncing() (*SyncStatus, error) {
-	return eth.client.RequestSyncStatus(MethodSyncing)
+	return eth.SyncingContext(context.Background())
+}
+
+// SyncingContext is like Syncing but takes a context.Context for deadlines and cancellation.
+func (eth Eth) SyncingContext(ctx context.Context) (*SyncStatus, error) {
+	return eth.client.RequestSyncStatusContext(ctx, MethodSyncing)
 }
 
 /*
@@ -317,5 +760,10 @@ func (eth Eth) Syncing() (*SyncStatus, error) {
 	Uninstalls a filter with given id. Should always be called when watch is no longer needed. Additonally Filters timeout when they aren’t requested with eth_getFilterChanges for a period of time.
 */
 func (eth Eth) UninstallFilter(filterID string) (bool, error) {
-	return eth.client.RequestBool(MethodUninstallFilter, filterID)
+	return eth.UninstallFilterContext(context.Background(), filterID)
+}
+
+// UninstallFilterContext is like UninstallFilter but takes a context.Context for deadlines and cancellation.
+func (eth Eth) UninstallFilterContext(ctx context.Context, filterID string) (bool, error) {
+	return eth.client.RequestBoolContext(ctx, MethodUninstallFilter, filterID)
 }
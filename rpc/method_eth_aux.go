@@ -1,9 +1,15 @@
 package rpc
 
 import (
+	"context"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
 )
 
 func (eth Eth) GetLatestBalance(address string, quantity *rpctypes.Quantity) (*rpctypes.EtherValue, error) {
-	return eth.GetBalance(address, rpctypes.QuantityLatest())
+	return eth.GetLatestBalanceContext(context.Background(), address, quantity)
+}
+
+// GetLatestBalanceContext is like GetLatestBalance but takes a context.Context for deadlines and cancellation.
+func (eth Eth) GetLatestBalanceContext(ctx context.Context, address string, quantity *rpctypes.Quantity) (*rpctypes.EtherValue, error) {
+	return eth.GetBalanceContext(ctx, address, rpctypes.QuantityLatest())
 }
@@ -1,24 +1,42 @@
 package rpc
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+
 	"github.com/Leondroids/go-ethereum-rpc/rpcutils"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
 )
 
 type EthCallParams struct {
-	From     string `json:"from,omitempty"`      // (optional) 20 Bytes - The address the transaction is send from.
-	To       string `json:"to,omitempty"`        // (optional when creating new contract) 20 Bytes - The address the transaction is directed to.
-	Gas      int64  `json:"gas,omitempty"`       // (optional) Integer of the gas provided for the transaction execution. eth_call consumes zero gas, but this parameter may be needed by some executions.
-	GasPrice int64  `json:"gas_price,omitempty"` // (optional) Integer of the gas price used for each paid gas.
-	Value    int64  `json:"value,omitempty"`     // (optional) Integer of the value sent with this transaction.
-	Data     string `json:"data,omitempty"`      // (optional) 4 byte hash of the method signature followed by encoded parameters.
+	From     string   `json:"from,omitempty"`     // (optional) 20 Bytes - The address the transaction is send from.
+	To       string   `json:"to,omitempty"`       // (optional when creating new contract) 20 Bytes - The address the transaction is directed to.
+	Gas      *big.Int `json:"gas,omitempty"`      // (optional) Integer of the gas provided for the transaction execution. eth_call consumes zero gas, but this parameter may be needed by some executions.
+	GasPrice *big.Int `json:"gasPrice,omitempty"` // (optional) Integer of the gas price used for each paid gas.
+	Value    *big.Int `json:"value,omitempty"`    // (optional) Integer of the value sent with this transaction.
+	Data     string   `json:"data,omitempty"`     // (optional) 4 byte hash of the method signature followed by encoded parameters.
 }
 
+// ToMap returns the transaction object of the call, unset fields are omitted and numbers are hex encoded.
 func (ecp *EthCallParams) ToMap() map[string]interface{} {
 	m := make(map[string]interface{}, 0)
 
-	m["to"] = ecp.To
-
+	if ecp.From != "" {
+		m["from"] = ecp.From
+	}
+	if ecp.To != "" {
+		m["to"] = ecp.To
+	}
+	if ecp.Gas != nil {
+		m["gas"] = rpctypes.BigIntToHex(ecp.Gas)
+	}
+	if ecp.GasPrice != nil {
+		m["gasPrice"] = rpctypes.BigIntToHex(ecp.GasPrice)
+	}
+	if ecp.Value != nil {
+		m["value"] = rpctypes.BigIntToHex(ecp.Value)
+	}
 	if ecp.Data != "" {
 		m["data"] = ecp.Data
 	}
@@ -56,6 +74,25 @@ func (ecp *EthCallParams) ToContractWithArgument(address string, functionSignatu
 	}, nil
 }
 
+// ToContractWithArguments calls the function with the given signature, e.g. "transfer(address,uint256)", with args ABI encoded.
+// See rpcutils.EncodeABIArguments for the go values accepted for each type.
+func (ecp *EthCallParams) ToContractWithArguments(address string, functionSignature string, args ...interface{}) (*EthCallParams, error) {
+	method, err := rpcutils.ParseABIMethodSignature(functionSignature)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := method.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EthCallParams{
+		Data: rpctypes.NewHexStringFromBytes(data).Hash(),
+		To:   address,
+	}, nil
+}
+
 func (ecp *EthCallParams) ToContractWithValue(address string, functionKeccak string) *EthCallParams {
 	return &EthCallParams{
 		Data: functionKeccak,
@@ -63,39 +100,185 @@ func (ecp *EthCallParams) ToContractWithValue(address string, functionKeccak str
 	}
 }
 
+// BlockNumberOrHash selects the block of a state query either by number or tag, or by hash as specified in EIP-1898.
+type BlockNumberOrHash struct {
+	Quantity         *rpctypes.Quantity
+	BlockHash        string
+	RequireCanonical bool // (only with BlockHash) fail if the block is not part of the canonical chain
+}
+
+func BlockByQuantity(quantity *rpctypes.Quantity) *BlockNumberOrHash {
+	return &BlockNumberOrHash{Quantity: quantity}
+}
+
+func BlockByHash(hash string, requireCanonical bool) *BlockNumberOrHash {
+	return &BlockNumberOrHash{BlockHash: hash, RequireCanonical: requireCanonical}
+}
+
+// Param returns the block parameter of the request: the number or tag, or a {"blockHash": ...} object. nil selects "latest".
+func (b *BlockNumberOrHash) Param() interface{} {
+	if b == nil {
+		return rpctypes.QuantityLatest().HexStringOrTag()
+	}
+
+	if b.BlockHash != "" {
+		m := map[string]interface{}{"blockHash": b.BlockHash}
+		if b.RequireCanonical {
+			m["requireCanonical"] = true
+		}
+		return m
+	}
+
+	if b.Quantity == nil {
+		return rpctypes.QuantityLatest().HexStringOrTag()
+	}
+
+	return b.Quantity.HexStringOrTag()
+}
+
+// AccountOverride replaces parts of an account's state for the duration of an eth_call (geth and compatible nodes).
+// State replaces the whole storage of the account, StateDiff only the given slots; both map 32 byte slots to 32 byte values.
+type AccountOverride struct {
+	Balance   *big.Int
+	Nonce     *uint64
+	Code      string
+	State     map[string]string
+	StateDiff map[string]string
+}
+
+// StateOverrides maps account addresses to the state they should have during an eth_call.
+type StateOverrides map[string]AccountOverride
+
+func (o AccountOverride) ToMap() map[string]interface{} {
+	m := make(map[string]interface{})
+
+	if o.Balance != nil {
+		m["balance"] = rpctypes.BigIntToHex(o.Balance)
+	}
+	if o.Nonce != nil {
+		m["nonce"] = rpctypes.BigIntToHex(new(big.Int).SetUint64(*o.Nonce))
+	}
+	if o.Code != "" {
+		m["code"] = o.Code
+	}
+	if o.State != nil {
+		m["state"] = o.State
+	}
+	if o.StateDiff != nil {
+		m["stateDiff"] = o.StateDiff
+	}
+
+	return m
+}
+
+func (overrides StateOverrides) ToMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(overrides))
+
+	for address, override := range overrides {
+		m[address] = override.ToMap()
+	}
+
+	return m
+}
+
 type EthEstimateGasParams struct {
-	From     string            `json:"from"`      // (optional) 20 Bytes - The address the transaction is send from.
-	To       string            `json:"to"`        // (optional when creating new contract) 20 Bytes - The address the transaction is directed to.
-	Gas      int64             `json:"gas"`       // (optional) Integer of the gas provided for the transaction execution. eth_call consumes zero gas, but this parameter may be needed by some executions.
-	GasPrice int64             `json:"gas_price"` // (optional) Integer of the gas price used for each paid gas.
-	Value    int64             `json:"value"`     // (optional) Integer of the value sent with this transaction.
-	Data     string            `json:"data"`      // (optional) 4 byte hash of the method signature followed by encoded parameters.
-	Quantity rpctypes.Quantity `json:"quantity"`  // (optional) Integer block number, or the string 'latest', 'earliest' or 'pending', see the default block parameter.
+	From     string             `json:"from,omitempty"`     // (optional) 20 Bytes - The address the transaction is send from.
+	To       string             `json:"to,omitempty"`       // (optional when creating new contract) 20 Bytes - The address the transaction is directed to.
+	Gas      *big.Int           `json:"gas,omitempty"`      // (optional) Integer of the gas provided for the transaction execution. eth_call consumes zero gas, but this parameter may be needed by some executions.
+	GasPrice *big.Int           `json:"gasPrice,omitempty"` // (optional) Integer of the gas price used for each paid gas.
+	Value    *big.Int           `json:"value,omitempty"`    // (optional) Integer of the value sent with this transaction.
+	Data     string             `json:"data,omitempty"`     // (optional) 4 byte hash of the method signature followed by encoded parameters.
+	Quantity *rpctypes.Quantity `json:"-"`                  // (optional) Integer block number, or the string 'latest', 'earliest' or 'pending', see the default block parameter.
+}
+
+// ToMap returns the transaction object of the request, encoded like the one of eth_call.
+func (p *EthEstimateGasParams) ToMap() map[string]interface{} {
+	call := &EthCallParams{
+		From:     p.From,
+		To:       p.To,
+		Gas:      p.Gas,
+		GasPrice: p.GasPrice,
+		Value:    p.Value,
+		Data:     p.Data,
+	}
+
+	return call.ToMap()
 }
 
+// Params returns the params of an eth_estimateGas request: the transaction object and, if set, the block.
+func (p *EthEstimateGasParams) Params() []interface{} {
+	if p.Quantity == nil {
+		return []interface{}{p.ToMap()}
+	}
+
+	return []interface{}{p.ToMap(), p.Quantity.HexStringOrTag()}
+}
+
+const MaxFilterTopics = 4 // LOG0 to LOG4 index up to four topics
+
+// NewFilterParams is the filter object of eth_newFilter, eth_getLogs and the logs subscription. Unset fields are
+// omitted, the node then uses its defaults (latest for fromBlock and toBlock, any address, any topics).
 type NewFilterParams struct {
-	FromBlock rpctypes.Quantity `json:"fromBlock"` // Tag - (optional) (default: latest) Integer block number, or 'latest' for the last mined block or 'pending', 'earliest' for not yet mined transactions.
-	ToBlock   rpctypes.Quantity `json:"toBlock"`   // Tag - (optional) (default: latest) Integer block number, or 'latest' for the last mined block or 'pending', 'earliest' for not yet mined transactions.
-	Address   string            `json:"address"`
-	Topics    [3][]string       `json:"topics"`
+	FromBlock *rpctypes.Quantity `json:"fromBlock,omitempty"` // Tag - (optional) (default: latest) Integer block number, or 'latest' for the last mined block or 'pending', 'earliest' for not yet mined transactions.
+	ToBlock   *rpctypes.Quantity `json:"toBlock,omitempty"`   // Tag - (optional) (default: latest) Integer block number, or 'latest' for the last mined block or 'pending', 'earliest' for not yet mined transactions.
+	BlockHash string             `json:"blockHash,omitempty"` // (optional) only logs of the block with this hash (EIP-234), cannot be used with FromBlock and ToBlock.
+	Addresses []string           `json:"address,omitempty"`   // (optional) logs of any of these contracts.
+	Topics    [][]string         `json:"topics,omitempty"`    // (optional) up to 4 topic positions, a log matches if every position contains one of its topics, nil or empty positions match anything.
+}
+
+// Validate checks that the filter has at most 4 topic positions and does not combine a block hash with a block range.
+func (p *NewFilterParams) Validate() error {
+	if p == nil {
+		return errors.New("filter params cannot be nil")
+	}
+
+	if p.BlockHash != "" && (p.FromBlock != nil || p.ToBlock != nil) {
+		return errors.New("filter with blockHash cannot have fromBlock or toBlock")
+	}
+
+	if len(p.Topics) > MaxFilterTopics {
+		return fmt.Errorf("filter has %v topic positions, at most %v are allowed", len(p.Topics), MaxFilterTopics)
+	}
+
+	return nil
 }
 
+// ToMap returns the filter object, unset fields and trailing wildcard topics are omitted.
+// A single address or topic is sent as string, several as list.
 func (p *NewFilterParams) ToMap() map[string]interface{} {
 	m := make(map[string]interface{})
 
-	m["fromBlock"] = p.FromBlock.HexStringOrTag()
-	m["toBlock"] = p.ToBlock.HexStringOrTag()
-	m["address"] = p.Address
+	if p.FromBlock != nil {
+		m["fromBlock"] = p.FromBlock.HexStringOrTag()
+	}
+	if p.ToBlock != nil {
+		m["toBlock"] = p.ToBlock.HexStringOrTag()
+	}
+	if p.BlockHash != "" {
+		m["blockHash"] = p.BlockHash
+	}
+	if address := GetInterfaceFromStringList(p.Addresses); address != nil {
+		m["address"] = address
+	}
 
-	t := make([]interface{}, 3)
+	topics := trimWildcardTopics(p.Topics)
+	if len(topics) > 0 {
+		t := make([]interface{}, len(topics))
+		for k, topic := range topics {
+			t[k] = GetInterfaceFromStringList(topic)
+		}
+		m["topics"] = t
+	}
 
-	t[0] = GetInterfaceFromStringList(p.Topics[0])
-	t[1] = GetInterfaceFromStringList(p.Topics[1])
-	t[2] = GetInterfaceFromStringList(p.Topics[2])
+	return m
+}
 
-	m["topics"] = t
+func trimWildcardTopics(topics [][]string) [][]string {
+	for len(topics) > 0 && len(topics[len(topics)-1]) == 0 {
+		topics = topics[:len(topics)-1]
+	}
 
-	return m
+	return topics
 }
 
 func GetInterfaceFromStringList(s []string) interface{} {
@@ -115,57 +298,66 @@ func GetInterfaceFromStringList(s []string) interface{} {
 }
 
 func CreateNewFilterParamsWithOneTopic(address string, from *rpctypes.Quantity, to *rpctypes.Quantity, topic string) *NewFilterParams {
-	topics := CreateNewFilterTopics([]string{topic}, nil, nil)
+	topics := CreateNewFilterTopics([]string{topic})
+	return CreateNewFilterParams(address, from, to, topics)
+}
+
+// CreateNewFilterParams returns a filter for logs of address (any address if empty) in the block range.
+func CreateNewFilterParams(address string, from *rpctypes.Quantity, to *rpctypes.Quantity, topics [][]string) *NewFilterParams {
 	return &NewFilterParams{
-		FromBlock: *from,
-		ToBlock:   *to,
-		Address:   address,
+		FromBlock: from,
+		ToBlock:   to,
+		Addresses: addressList(address),
 		Topics:    topics,
 	}
 }
 
-func CreateNewFilterParams(address string, from *rpctypes.Quantity, to *rpctypes.Quantity, topics [3][]string) *NewFilterParams {
+// CreateNewFilterParamsAtBlockHash returns a filter for logs of any of the addresses in the block with the given hash.
+func CreateNewFilterParamsAtBlockHash(addresses []string, blockHash string, topics [][]string) *NewFilterParams {
 	return &NewFilterParams{
-		FromBlock: *from,
-		ToBlock:   *to,
-		Address:   address,
+		BlockHash: blockHash,
+		Addresses: addresses,
 		Topics:    topics,
 	}
 }
 
-func CreateNewFilterTopics(topic1 []string, topic2 []string, topic3 []string) [3][]string {
-	return [3][]string{
-		topic1, topic2, topic3,
+func addressList(address string) []string {
+	if address == "" {
+		return nil
 	}
+
+	return []string{address}
+}
+
+// CreateNewFilterTopics returns the topic positions of a filter, nil or empty positions are wildcards.
+func CreateNewFilterTopics(topics ...[]string) [][]string {
+	return topics
 }
 
+// FilterTopicBuilder collects the alternatives of topic positions, positions without topics are wildcards.
 type FilterTopicBuilder struct {
-	topic1 []string
-	topic2 []string
-	topic3 []string
+	topics [][]string
 }
 
 func (ftb *FilterTopicBuilder) Create() *FilterTopicBuilder {
-	ftb.topic1 = make([]string, 0)
-	ftb.topic2 = make([]string, 0)
-	ftb.topic3 = make([]string, 0)
+	ftb.topics = nil
 	return ftb
 }
 
+// AddTopic adds topic as alternative at position pos, which must not be negative. Positions beyond 3 are kept,
+// Validate rejects filters with them.
 func (ftb *FilterTopicBuilder) AddTopic(pos int, topic string) *FilterTopicBuilder {
-	switch pos {
-	case 0:
-		ftb.topic1 = append(ftb.topic1, topic)
-	case 1:
-		ftb.topic2 = append(ftb.topic2, topic)
-	case 2:
-		ftb.topic3 = append(ftb.topic3, topic)
+	for len(ftb.topics) <= pos {
+		ftb.topics = append(ftb.topics, nil)
 	}
+	ftb.topics[pos] = append(ftb.topics[pos], topic)
 	return ftb
 }
 
-func (ftb *FilterTopicBuilder) Build() [3][]string {
-	return [3][]string{
-		ftb.topic1, ftb.topic2, ftb.topic3,
-	}
+// Build returns the topic positions without trailing wildcards.
+func (ftb *FilterTopicBuilder) Build() [][]string {
+	topics := make([][]string, len(ftb.topics))
+	copy(topics, ftb.topics)
+
+	return trimWildcardTopics(topics)
 }
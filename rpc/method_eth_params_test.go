@@ -1,11 +1,177 @@
 package rpc
 
 import (
+	"encoding/json"
+	"math/big"
 	"testing"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/Leondroids/go-ethereum-rpc/storage"
 )
 
 func TestNewFilterParams_ToMap(t *testing.T) {
 	params := CreateNewFilterParams("address", rpctypes.QuantityLatest(), rpctypes.QuantityLatest(), CreateNewFilterTopics([]string{"t11", "t12"}, []string{"t21"}, []string{}))
-	t.Errorf("%+v", params.ToMap())
+
+	js, _ := json.Marshal(params.ToMap())
+	expected := `{"address":"address","fromBlock":"latest","toBlock":"latest","topics":[["t11","t12"],"t21"]}`
+
+	if string(js) != expected {
+		t.Errorf("[Expected: %v, Actual: %v]", expected, string(js))
+	}
+}
+
+func TestNewFilterParams_ToMapOmitsUnset(t *testing.T) {
+	tests := []struct {
+		params   *NewFilterParams
+		expected string
+	}{
+		{&NewFilterParams{}, `{}`},
+		{&NewFilterParams{Topics: CreateNewFilterTopics(nil, nil)}, `{}`},
+		{&NewFilterParams{FromBlock: rpctypes.QuantityBlock(16)}, `{"fromBlock":"0x10"}`},
+		{
+			&NewFilterParams{Addresses: []string{"a1", "a2"}, Topics: CreateNewFilterTopics(nil, []string{"t2"}, nil, []string{"t41", "t42"})},
+			`{"address":["a1","a2"],"topics":[null,"t2",null,["t41","t42"]]}`,
+		},
+		{
+			CreateNewFilterParamsAtBlockHash([]string{"a1"}, "0x01", new(FilterTopicBuilder).Create().AddTopic(0, "t1").AddTopic(3, "t4").Build()),
+			`{"address":"a1","blockHash":"0x01","topics":["t1",null,null,"t4"]}`,
+		},
+	}
+
+	for _, test := range tests {
+		if err := test.params.Validate(); err != nil {
+			t.Error(err)
+			return
+		}
+
+		js, _ := json.Marshal(test.params.ToMap())
+		if string(js) != test.expected {
+			t.Errorf("[Expected: %v, Actual: %v]", test.expected, string(js))
+		}
+	}
+}
+
+func TestNewFilterParams_Validate(t *testing.T) {
+	invalid := []*NewFilterParams{
+		nil,
+		{BlockHash: "0x01", FromBlock: rpctypes.QuantityBlock(1)},
+		{BlockHash: "0x01", ToBlock: rpctypes.QuantityLatest()},
+		{Topics: CreateNewFilterTopics(nil, nil, nil, nil, []string{"t5"})},
+		{Topics: new(FilterTopicBuilder).Create().AddTopic(0, "t1").AddTopic(4, "t5").Build()},
+	}
+
+	for _, params := range invalid {
+		if err := params.Validate(); err == nil {
+			t.Errorf("invalid filter accepted [Expected: %v, Actual: %v]", "error", params)
+		}
+	}
+
+	if _, err := NewRPCClient("http://localhost:0").Eth.GetLogs(invalid[1]); err == nil {
+		t.Errorf("invalid filter sent [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestEthCallParams_ToMap(t *testing.T) {
+	params := &EthCallParams{
+		From:  "0x407d73d8a49eeb85d32cf465507dd71d507100c1",
+		To:    "0xd780ae2bf04cd96e577d3d014762f831d97129d0",
+		Gas:   big.NewInt(100000),
+		Value: new(big.Int).Lsh(big.NewInt(1), 70),
+		Data:  "0x115976c4",
+	}
+
+	js, _ := json.Marshal(params.ToMap())
+	expected := `{"data":"0x115976c4","from":"0x407d73d8a49eeb85d32cf465507dd71d507100c1","gas":"0x186a0","to":"0xd780ae2bf04cd96e577d3d014762f831d97129d0","value":"0x400000000000000000"}`
+
+	if string(js) != expected {
+		t.Errorf("[Expected: %v, Actual: %v]", expected, string(js))
+	}
+}
+
+func TestEthCallParams_ToContractWithArguments(t *testing.T) {
+	params, err := new(EthCallParams).ToContractWithArguments("0xd780ae2bf04cd96e577d3d014762f831d97129d0", "transfer(address,uint256)", "0x407d73d8a49eeb85d32cf465507dd71d507100c1", big.NewInt(1000))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := "0xa9059cbb" +
+		"000000000000000000000000407d73d8a49eeb85d32cf465507dd71d507100c1" +
+		"00000000000000000000000000000000000000000000000000000000000003e8"
+	if params.Data != expected || params.To != "0xd780ae2bf04cd96e577d3d014762f831d97129d0" {
+		t.Errorf("[Expected: %v, Actual: %v]", expected, params.Data)
+	}
+
+	if _, err := new(EthCallParams).ToContractWithArguments("0xd780ae2bf04cd96e577d3d014762f831d97129d0", "transfer(address,uint256)", "0x407d73d8a49eeb85d32cf465507dd71d507100c1"); err == nil {
+		t.Errorf("missing argument accepted [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestEth_CallAtRequest(t *testing.T) {
+	var request RPCRequest
+	server, _ := testServer(func(r RPCRequest) RPCResponse {
+		request = r
+		return RPCResponse{Result: "0x01"}
+	})
+	defer server.Close()
+
+	nonce := uint64(7)
+	overrides := StateOverrides{
+		"0xd780ae2bf04cd96e577d3d014762f831d97129d0": AccountOverride{
+			Balance:   big.NewInt(1),
+			Nonce:     &nonce,
+			StateDiff: map[string]string{"0x00": "0x01"},
+		},
+	}
+
+	_, err := NewRPCClient(server.URL).Eth.CallAt(
+		&EthCallParams{To: "0xd780ae2bf04cd96e577d3d014762f831d97129d0", Data: "0x115976c4"},
+		BlockByHash("0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6", true),
+		overrides,
+	)
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	js, _ := json.Marshal(request.Params)
+	expected := `[{"data":"0x115976c4","to":"0xd780ae2bf04cd96e577d3d014762f831d97129d0"},` +
+		`{"blockHash":"0x88e96d4537bea4d9c05d12549907b32561d3bf31f45aae734cdc119f13406cb6","requireCanonical":true},` +
+		`{"0xd780ae2bf04cd96e577d3d014762f831d97129d0":{"balance":"0x1","nonce":"0x7","stateDiff":{"0x00":"0x01"}}}]`
+
+	if string(js) != expected {
+		t.Errorf("[Expected: %v, Actual: %v]", expected, string(js))
+	}
+}
+
+func TestEth_GetStorageAtRequest(t *testing.T) {
+	var request RPCRequest
+	server, _ := testServer(func(r RPCRequest) RPCResponse {
+		request = r
+		return RPCResponse{Result: "0x0000000000000000000000009d8a62f656a8d1615c1294fd71e9cfb3e4855a4f"}
+	})
+	defer server.Close()
+
+	result, err := NewRPCClient(server.URL).Eth.GetStorageAt("0xd780ae2bf04cd96e577d3d014762f831d97129d0", storage.ImplementationSlot, rpctypes.QuantityBlock(300))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	js, _ := json.Marshal(request.Params)
+	expected := `["0xd780ae2bf04cd96e577d3d014762f831d97129d0","0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc","0x12c"]`
+
+	if string(js) != expected {
+		t.Errorf("[Expected: %v, Actual: %v]", expected, string(js))
+	}
+
+	implementation, err := storage.DecodeAddress(storage.Word(result))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if implementation.String() != "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f" {
+		t.Errorf("[Expected: %v, Actual: %v]", "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f", implementation.String())
+	}
 }
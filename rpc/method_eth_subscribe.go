@@ -0,0 +1,195 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+const (
+	SubscriptionNewHeads               = "newHeads"
+	SubscriptionLogs                   = "logs"
+	SubscriptionNewPendingTransactions = "newPendingTransactions"
+	SubscriptionSyncing                = "syncing"
+)
+
+/*
+	rpc method: "eth_subscribe" with "newHeads"
+	Delivers the header of every new block on the given channel, including blocks of chain reorganizations.
+	Only available on websocket and ipc endpoints. The headers contain no transactions.
+*/
+func (eth Eth) SubscribeNewHeads(headers chan<- rpctypes.EtherBlock) (*Subscription, error) {
+	return eth.SubscribeNewHeadsContext(context.Background(), headers)
+}
+
+// SubscribeNewHeadsContext is like SubscribeNewHeads but takes a context.Context for deadlines and cancellation.
+// The context only covers the eth_subscribe request, not the lifetime of the subscription.
+func (eth Eth) SubscribeNewHeadsContext(ctx context.Context, headers chan<- rpctypes.EtherBlock) (*Subscription, error) {
+	sub := newSubscription(
+		[]interface{}{SubscriptionNewHeads},
+		func(result json.RawMessage) (interface{}, error) {
+			raw := RPCEtherBlockRawWithTransactionHash{}
+			if err := json.Unmarshal(result, &raw); err != nil {
+				return nil, err
+			}
+			return raw.ToEtherBlock()
+		},
+		func(value interface{}, quit <-chan struct{}) bool {
+			select {
+			case headers <- *value.(*rpctypes.EtherBlock):
+				return true
+			case <-quit:
+				return false
+			}
+		},
+	)
+
+	if err := eth.client.subscribe(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+/*
+	rpc method: "eth_subscribe" with "logs"
+	Delivers every new log matching the address and topics of the filter on the given channel.
+	FromBlock, ToBlock and BlockHash of the filter are ignored. Logs of reorganized blocks are sent again with Removed set.
+*/
+func (eth Eth) SubscribeLogs(params *NewFilterParams, logs chan<- rpctypes.EtherLog) (*Subscription, error) {
+	return eth.SubscribeLogsContext(context.Background(), params, logs)
+}
+
+// SubscribeLogsContext is like SubscribeLogs but takes a context.Context for deadlines and cancellation.
+// The context only covers the eth_subscribe request, not the lifetime of the subscription.
+func (eth Eth) SubscribeLogsContext(ctx context.Context, params *NewFilterParams, logs chan<- rpctypes.EtherLog) (*Subscription, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	filter := params.ToMap()
+	delete(filter, "fromBlock")
+	delete(filter, "toBlock")
+	delete(filter, "blockHash")
+
+	sub := newSubscription(
+		[]interface{}{SubscriptionLogs, filter},
+		func(result json.RawMessage) (interface{}, error) {
+			return new(rpctypes.EtherLogRaw).FromJSON(result)
+		},
+		func(value interface{}, quit <-chan struct{}) bool {
+			select {
+			case logs <- *value.(*rpctypes.EtherLog):
+				return true
+			case <-quit:
+				return false
+			}
+		},
+	)
+
+	if err := eth.client.subscribe(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+/*
+	rpc method: "eth_subscribe" with "newPendingTransactions"
+	Delivers the hash of every transaction that is added to the pending state of the node.
+*/
+func (eth Eth) SubscribeNewPendingTransactions(hashes chan<- rpctypes.HexString) (*Subscription, error) {
+	return eth.SubscribeNewPendingTransactionsContext(context.Background(), hashes)
+}
+
+// SubscribeNewPendingTransactionsContext is like SubscribeNewPendingTransactions but takes a context.Context for deadlines and cancellation.
+// The context only covers the eth_subscribe request, not the lifetime of the subscription.
+func (eth Eth) SubscribeNewPendingTransactionsContext(ctx context.Context, hashes chan<- rpctypes.HexString) (*Subscription, error) {
+	sub := newSubscription(
+		[]interface{}{SubscriptionNewPendingTransactions},
+		func(result json.RawMessage) (interface{}, error) {
+			hash := ""
+			if err := json.Unmarshal(result, &hash); err != nil {
+				return nil, err
+			}
+			return rpctypes.NewHexString(hash)
+		},
+		func(value interface{}, quit <-chan struct{}) bool {
+			select {
+			case hashes <- *value.(*rpctypes.HexString):
+				return true
+			case <-quit:
+				return false
+			}
+		},
+	)
+
+	if err := eth.client.subscribe(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+/*
+	rpc method: "eth_subscribe" with "syncing"
+	Delivers a SyncStatus whenever the node starts or stops synchronizing.
+*/
+func (eth Eth) SubscribeSyncing(statuses chan<- SyncStatus) (*Subscription, error) {
+	return eth.SubscribeSyncingContext(context.Background(), statuses)
+}
+
+// SubscribeSyncingContext is like SubscribeSyncing but takes a context.Context for deadlines and cancellation.
+// The context only covers the eth_subscribe request, not the lifetime of the subscription.
+func (eth Eth) SubscribeSyncingContext(ctx context.Context, statuses chan<- SyncStatus) (*Subscription, error) {
+	sub := newSubscription(
+		[]interface{}{SubscriptionSyncing},
+		decodeSyncingNotification,
+		func(value interface{}, quit <-chan struct{}) bool {
+			select {
+			case statuses <- *value.(*SyncStatus):
+				return true
+			case <-quit:
+				return false
+			}
+		},
+	)
+
+	if err := eth.client.subscribe(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// decodeSyncingNotification handles both the plain false / status object of parity and
+// geth's {"syncing": bool, "status": {...}} wrapper.
+func decodeSyncingNotification(result json.RawMessage) (interface{}, error) {
+	syncing := false
+	if err := json.Unmarshal(result, &syncing); err == nil {
+		return &SyncStatus{IsSyncing: syncing}, nil
+	}
+
+	wrapped := struct {
+		Syncing *bool           `json:"syncing"`
+		Status  json.RawMessage `json:"status"`
+	}{}
+
+	if err := json.Unmarshal(result, &wrapped); err != nil {
+		return nil, err
+	}
+
+	if wrapped.Syncing != nil {
+		if !*wrapped.Syncing {
+			return &SyncStatus{IsSyncing: false}, nil
+		}
+		if wrapped.Status == nil {
+			return nil, fmt.Errorf("syncing notification without status: %s", result)
+		}
+		result = wrapped.Status
+	}
+
+	return new(SyncStatusRaw).fromJSON(result)
+}
@@ -331,9 +331,17 @@ func TestEth_GetFilterLogs(t *testing.T) {
 		BlockNumber:      5730114,
 		BlockHash:        *bh,
 		TransactionHash:  *th,
-		TransactionIndex: 50,
+		LogIndex:         50,
 	}
 
+	// the recorded log lacks its transaction index, it must be the index of the transaction that emitted it
+	tx, err := NewRPCClient(config().address).Eth.GetTransactionByHash(th.String())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expectedLogs.TransactionIndex = tx.TransactionIndex
+
 	if err := expectedLogs.Compare(result[0]); err != nil {
 		t.Error(err)
 	}
@@ -366,8 +374,16 @@ func TestEth_GetLogs(t *testing.T) {
 		BlockNumber:      5730114,
 		BlockHash:        *bh,
 		TransactionHash:  *th,
-		TransactionIndex: 50,
+		LogIndex:         50,
+	}
+
+	// the recorded log lacks its transaction index, it must be the index of the transaction that emitted it
+	tx, err := NewRPCClient(config().address).Eth.GetTransactionByHash(th.String())
+	if err != nil {
+		t.Error(err)
+		return
 	}
+	expectedLogs.TransactionIndex = tx.TransactionIndex
 
 	if err := expectedLogs.Compare(result[0]); err != nil {
 		t.Error(err)
@@ -375,7 +391,7 @@ func TestEth_GetLogs(t *testing.T) {
 }
 
 func TestEth_GetStorageAt(t *testing.T) {
-	result, err := NewRPCClient(config().address).Eth.GetStorageAt("0xd780ae2bf04cd96e577d3d014762f831d97129d0", 1, rpctypes.QuantityLatest())
+	result, err := NewRPCClient(config().address).Eth.GetStorageAt("0xd780ae2bf04cd96e577d3d014762f831d97129d0", big.NewInt(1), rpctypes.QuantityLatest())
 
 	if err != nil {
 		t.Error(err)
@@ -1,5 +1,7 @@
 package rpc
 
+import "context"
+
 const (
 	MethodNetListening = "net_listening"
 	MethodNetPeerCount = "net_peerCount"
@@ -15,7 +17,12 @@ type Net struct {
 	returns true if client is actively listening for network connections.
  */
 func (net *Net) NetListening() (bool, error) {
-	return net.client.RequestBool(MethodNetListening)
+	return net.NetListeningContext(context.Background())
+}
+
+// NetListeningContext is like NetListening but takes a context.Context for deadlines and cancellation.
+func (net *Net) NetListeningContext(ctx context.Context) (bool, error) {
+	return net.client.RequestBoolContext(ctx, MethodNetListening)
 }
 
 /*
@@ -23,7 +30,12 @@ func (net *Net) NetListening() (bool, error) {
 	returns true if client is actively listening for network connections.
  */
 func (net *Net) NetPeerCount() (int64, error) {
-	return net.client.RequestInt64(MethodNetPeerCount)
+	return net.NetPeerCountContext(context.Background())
+}
+
+// NetPeerCountContext is like NetPeerCount but takes a context.Context for deadlines and cancellation.
+func (net *Net) NetPeerCountContext(ctx context.Context) (int64, error) {
+	return net.client.RequestInt64Context(ctx, MethodNetPeerCount)
 }
 
 /*
@@ -36,5 +48,10 @@ func (net *Net) NetPeerCount() (int64, error) {
 	"42": Kovan Testnet
  */
 func (net *Net) NetVersion() (string, error) {
-	return net.client.RequestString(MethodNetVersion)
+	return net.NetVersionContext(context.Background())
+}
+
+// NetVersionContext is like NetVersion but takes a context.Context for deadlines and cancellation.
+func (net *Net) NetVersionContext(ctx context.Context) (string, error) {
+	return net.client.RequestStringContext(ctx, MethodNetVersion)
 }
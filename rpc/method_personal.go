@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
 )
 
@@ -25,7 +26,12 @@ type Personal struct {
 	curl --data '{"method":"personal_listAccounts","params":[],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
 */
 func (personal *Personal) ListAccounts() ([]rpctypes.HexString, error) {
-	return personal.client.RequestHexStringList(MethodPersonalListAccounts)
+	return personal.ListAccountsContext(context.Background())
+}
+
+// ListAccountsContext is like ListAccounts but takes a context.Context for deadlines and cancellation.
+func (personal *Personal) ListAccountsContext(ctx context.Context) ([]rpctypes.HexString, error) {
+	return personal.client.RequestHexStringListContext(ctx, MethodPersonalListAccounts)
 }
 
 /*
@@ -36,7 +42,12 @@ func (personal *Personal) ListAccounts() ([]rpctypes.HexString, error) {
 	curl --data '{"method":"personal_newAccount","params":["hunter2"],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
  */
 func (personal *Personal) NewAccount(password string) (*rpctypes.HexString, error) {
-	return personal.client.RequestHexString(MethodPersonalNewAccount, password)
+	return personal.NewAccountContext(context.Background(), password)
+}
+
+// NewAccountContext is like NewAccount but takes a context.Context for deadlines and cancellation.
+func (personal *Personal) NewAccountContext(ctx context.Context, password string) (*rpctypes.HexString, error) {
+	return personal.client.RequestHexStringContext(ctx, MethodPersonalNewAccount, password)
 }
 
 /*
@@ -46,5 +57,10 @@ func (personal *Personal) NewAccount(password string) (*rpctypes.HexString, erro
 	curl --data '{"method":"personal_sendTransaction","params":[{"from":"0x00a329c0648769a73afac7f9381e08fb43dbea72","to":"0x7c9ef46acd73a8225c417ea1ba69a65a74be0d80","data":"0x0","value":"0x186a0"},"hunter2"],"id":1,"jsonrpc":"2.0"}' -H "Content-Type: application/json" -X POST localhost:8545
  */
 func (personal *Personal) SendTransaction(sendTransactionParams *SendTransaction, password string) (*rpctypes.HexString, error) {
-	return personal.client.RequestHexString(MethodPersonalSendTransaction, sendTransactionParams.ToMap(), password)
+	return personal.SendTransactionContext(context.Background(), sendTransactionParams, password)
+}
+
+// SendTransactionContext is like SendTransaction but takes a context.Context for deadlines and cancellation.
+func (personal *Personal) SendTransactionContext(ctx context.Context, sendTransactionParams *SendTransaction, password string) (*rpctypes.HexString, error) {
+	return personal.client.RequestHexStringContext(ctx, MethodPersonalSendTransaction, sendTransactionParams.ToMap(), password)
 }
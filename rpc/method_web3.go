@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
 )
 
@@ -18,7 +19,12 @@ type Web3 struct {
 	returns string, the current Client version.
  */
 func (web3 Web3) ClientVersion() (string, error) {
-	return web3.client.RequestString(MethodWeb3ClientVersion)
+	return web3.ClientVersionContext(context.Background())
+}
+
+// ClientVersionContext is like ClientVersion but takes a context.Context for deadlines and cancellation.
+func (web3 Web3) ClientVersionContext(ctx context.Context) (string, error) {
+	return web3.client.RequestStringContext(ctx, MethodWeb3ClientVersion)
 }
 
 /*
@@ -26,5 +32,10 @@ func (web3 Web3) ClientVersion() (string, error) {
 	returns string, Keccak-256 (not the standardized SHA3-256) of the given data.
  */
 func (web3 Web3) Sha3(b []byte) (string, error) {
-	return web3.client.RequestString(MethodWeb3Sha3, rpctypes.ByteToHex(b))
+	return web3.Sha3Context(context.Background(), b)
+}
+
+// Sha3Context is like Sha3 but takes a context.Context for deadlines and cancellation.
+func (web3 Web3) Sha3Context(ctx context.Context, b []byte) (string, error) {
+	return web3.client.RequestStringContext(ctx, MethodWeb3Sha3, rpctypes.ByteToHex(b))
 }
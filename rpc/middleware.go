@@ -0,0 +1,172 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type CallFunc func(ctx context.Context, request *RPCRequest) (*RPCResponse, error)
+
+type NotifyFunc func(ctx context.Context, notification *RPCNotification) error
+
+type BatchFunc func(ctx context.Context, requests []interface{}) ([]RPCResponse, error)
+
+// Interceptor wraps the round trips of a Client. Each method receives the next element of the chain and decides
+// whether and with which request to call it: an interceptor may log or time the round trip, rewrite the request
+// or the response, or short-circuit by returning a response without calling next at all.
+//
+// Interceptors see every call, notification and batch of the client once, before retries and rate limits are applied.
+// Subscriptions are not intercepted.
+type Interceptor interface {
+	InterceptCall(ctx context.Context, request *RPCRequest, next CallFunc) (*RPCResponse, error)
+	InterceptNotify(ctx context.Context, notification *RPCNotification, next NotifyFunc) error
+	InterceptBatch(ctx context.Context, requests []interface{}, next BatchFunc) ([]RPCResponse, error)
+}
+
+// InterceptorFuncs implements Interceptor with optional functions, nil functions pass the round trip on unchanged.
+type InterceptorFuncs struct {
+	Call   func(ctx context.Context, request *RPCRequest, next CallFunc) (*RPCResponse, error)
+	Notify func(ctx context.Context, notification *RPCNotification, next NotifyFunc) error
+	Batch  func(ctx context.Context, requests []interface{}, next BatchFunc) ([]RPCResponse, error)
+}
+
+func (f InterceptorFuncs) InterceptCall(ctx context.Context, request *RPCRequest, next CallFunc) (*RPCResponse, error) {
+	if f.Call == nil {
+		return next(ctx, request)
+	}
+	return f.Call(ctx, request, next)
+}
+
+func (f InterceptorFuncs) InterceptNotify(ctx context.Context, notification *RPCNotification, next NotifyFunc) error {
+	if f.Notify == nil {
+		return next(ctx, notification)
+	}
+	return f.Notify(ctx, notification, next)
+}
+
+func (f InterceptorFuncs) InterceptBatch(ctx context.Context, requests []interface{}, next BatchFunc) ([]RPCResponse, error) {
+	if f.Batch == nil {
+		return next(ctx, requests)
+	}
+	return f.Batch(ctx, requests, next)
+}
+
+// Use appends interceptors to the client's chain. The first interceptor is the outermost one:
+// it sees the request first and the response last.
+func (client *Client) Use(interceptors ...Interceptor) {
+	client.interceptorsMu.Lock()
+	defer client.interceptorsMu.Unlock()
+
+	client.interceptors = append(client.interceptors, interceptors...)
+}
+
+// NewTimingInterceptor returns an interceptor which reports the duration and outcome of every round trip.
+// For batches, method is "batch" and err is the error of the whole batch.
+func NewTimingInterceptor(observe func(method string, duration time.Duration, err error)) Interceptor {
+	return InterceptorFuncs{
+		Call: func(ctx context.Context, request *RPCRequest, next CallFunc) (*RPCResponse, error) {
+			start := time.Now()
+			response, err := next(ctx, request)
+
+			cause := err
+			if err == nil && response.Error != nil {
+				cause = response.Error
+			}
+
+			observe(request.Method, time.Since(start), cause)
+			return response, err
+		},
+		Notify: func(ctx context.Context, notification *RPCNotification, next NotifyFunc) error {
+			start := time.Now()
+			err := next(ctx, notification)
+			observe(notification.Method, time.Since(start), err)
+			return err
+		},
+		Batch: func(ctx context.Context, requests []interface{}, next BatchFunc) ([]RPCResponse, error) {
+			start := time.Now()
+			responses, err := next(ctx, requests)
+			observe("batch", time.Since(start), err)
+			return responses, err
+		},
+	}
+}
+
+type requestHeadersKey struct{}
+
+// WithRequestHeader returns a context which adds the given header to http requests made with it,
+// e.g. a tracing header set by an interceptor. Other transports ignore it.
+func WithRequestHeader(ctx context.Context, key string, value string) context.Context {
+	headers := http.Header{}
+	for k, v := range requestHeaders(ctx) {
+		headers[k] = v
+	}
+	headers.Set(key, value)
+
+	return context.WithValue(ctx, requestHeadersKey{}, headers)
+}
+
+func requestHeaders(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(requestHeadersKey{}).(http.Header)
+	return headers
+}
+
+func (client *Client) chain() []Interceptor {
+	client.interceptorsMu.RLock()
+	defer client.interceptorsMu.RUnlock()
+
+	return client.interceptors
+}
+
+// call sends a single request through the interceptors, then retries and rate limits.
+func (client *Client) call(ctx context.Context, request *RPCRequest) (*RPCResponse, error) {
+	next := CallFunc(client.retryCall)
+
+	interceptors := client.chain()
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := interceptors[i], next
+		next = func(ctx context.Context, request *RPCRequest) (*RPCResponse, error) {
+			return interceptor.InterceptCall(ctx, request, inner)
+		}
+	}
+
+	return next(ctx, request)
+}
+
+// notify sends a notification through the interceptors and rate limits.
+func (client *Client) notify(ctx context.Context, notification *RPCNotification) error {
+	next := NotifyFunc(func(ctx context.Context, notification *RPCNotification) error {
+		release, err := client.limits.acquire(ctx, notification.Method)
+		if err != nil {
+			return err
+		}
+		defer release()
+
+		return client.transport.Notify(ctx, notification)
+	})
+
+	interceptors := client.chain()
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := interceptors[i], next
+		next = func(ctx context.Context, notification *RPCNotification) error {
+			return interceptor.InterceptNotify(ctx, notification, inner)
+		}
+	}
+
+	return next(ctx, notification)
+}
+
+// batch sends a batch request through the interceptors, then retries and rate limits.
+func (client *Client) batch(ctx context.Context, requests []interface{}) ([]RPCResponse, error) {
+	next := BatchFunc(client.retryBatch)
+
+	interceptors := client.chain()
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := interceptors[i], next
+		next = func(ctx context.Context, requests []interface{}) ([]RPCResponse, error) {
+			return interceptor.InterceptBatch(ctx, requests, inner)
+		}
+	}
+
+	return next(ctx, requests)
+}
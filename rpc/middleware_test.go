@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_InterceptorOrder(t *testing.T) {
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		return RPCResponse{Result: "0x1"}
+	})
+	defer server.Close()
+
+	trace := make([]string, 0)
+	tracing := func(name string) Interceptor {
+		return InterceptorFuncs{Call: func(ctx context.Context, request *RPCRequest, next CallFunc) (*RPCResponse, error) {
+			trace = append(trace, name+">"+request.Method)
+			response, err := next(ctx, request)
+			trace = append(trace, name+"<")
+			return response, err
+		}}
+	}
+
+	client := NewRPCClient(server.URL)
+	client.Use(tracing("a"), tracing("b"))
+
+	if _, err := client.Eth.BlockNumber(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if actual := strings.Join(trace, " "); actual != "a>eth_blockNumber b>eth_blockNumber b< a<" {
+		t.Errorf("wrong order [Expected: %v, Actual: %v]", "a>eth_blockNumber b>eth_blockNumber b< a<", actual)
+	}
+}
+
+func TestClient_InterceptorShortCircuit(t *testing.T) {
+	server, attempts := testServer(func(request RPCRequest) RPCResponse {
+		return RPCResponse{Result: "0x1"}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.Use(InterceptorFuncs{Call: func(ctx context.Context, request *RPCRequest, next CallFunc) (*RPCResponse, error) {
+		if request.Method == MethodEthBlockNumber {
+			return &RPCResponse{JSONRPC: "2.0", ID: request.ID, Result: "0x2a"}, nil
+		}
+		return next(ctx, request)
+	}})
+
+	blockNumber, err := client.Eth.BlockNumber()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if blockNumber != 42 || *attempts != 0 {
+		t.Errorf("request not short-circuited [Expected: %v after %v requests, Actual: %v after %v requests]", 42, 0, blockNumber, *attempts)
+	}
+}
+
+func TestClient_InterceptorHeaderAndTiming(t *testing.T) {
+	var traceHeader atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header := r.Header.Get("X-Trace-Id"); header != "" {
+			traceHeader.Store(header)
+		}
+		w.Write([]byte(`[{"jsonrpc":"2.0","id":0,"result":"0x1"}]`))
+	}))
+	defer server.Close()
+
+	observed := make([]string, 0)
+	client := NewRPCClient(server.URL)
+	client.Use(
+		NewTimingInterceptor(func(method string, duration time.Duration, err error) {
+			observed = append(observed, method)
+		}),
+		InterceptorFuncs{Batch: func(ctx context.Context, requests []interface{}, next BatchFunc) ([]RPCResponse, error) {
+			return next(WithRequestHeader(ctx, "X-Trace-Id", "42"), requests)
+		}},
+	)
+
+	if _, err := client.Batch(client.NewRPCRequestObject(MethodEthBlockNumber)); err != nil {
+		t.Error(err)
+		return
+	}
+	client.Notification(MethodEthBlockNumber)
+
+	if header, _ := traceHeader.Load().(string); header != "42" {
+		t.Errorf("header not injected [Expected: %v, Actual: %v]", "42", header)
+	}
+
+	if actual := strings.Join(observed, " "); actual != "batch eth_blockNumber" {
+		t.Errorf("wrong observations [Expected: %v, Actual: %v]", "batch eth_blockNumber", actual)
+	}
+}
@@ -0,0 +1,283 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// node error messages which mean that a nonce was already used
+var (
+	nonceTooLowMessages = []string{
+		"nonce too low",
+		"replacement transaction underpriced",
+		"replacement underpriced",
+		"transaction with same nonce in the queue", // parity
+		"nonce has already been used",
+	}
+	alreadyKnownMessages = []string{
+		"already known",
+		"known transaction",
+		"already imported", // parity
+	}
+)
+
+// maxNonceAttempts is how often Send signs a transaction with a new nonce after a nonce error.
+const maxNonceAttempts = 3
+
+// NonceManager hands out the nonces of accounts which send transactions concurrently.
+//
+// The first nonce of an account is seeded from eth_getTransactionCount(address, "pending"), later ones are counted up locally.
+// A nonce whose transaction could not be sent is given back with Release and handed out again before new ones,
+// so that no gap blocks the following transactions.
+//
+// If GasStrategy is set, Send prices transactions without gas price with its suggestion of GasSpeed.
+type NonceManager struct {
+	client *Client
+
+	GasStrategy GasPriceStrategy
+	GasSpeed    GasSpeed
+
+	mu       sync.Mutex
+	accounts map[string]*nonceAccount
+}
+
+type nonceAccount struct {
+	mu       sync.Mutex
+	seeded   bool
+	next     uint64
+	released []uint64 // sorted
+}
+
+func NewNonceManager(client *Client) *NonceManager {
+	return &NonceManager{
+		client:   client,
+		accounts: make(map[string]*nonceAccount),
+	}
+}
+
+func (nm *NonceManager) account(address string) *nonceAccount {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	key := strings.ToLower(address)
+
+	account, ok := nm.accounts[key]
+	if !ok {
+		account = new(nonceAccount)
+		nm.accounts[key] = account
+	}
+
+	return account
+}
+
+// Next returns the next nonce of the address.
+func (nm *NonceManager) Next(address string) (uint64, error) {
+	return nm.NextContext(context.Background(), address)
+}
+
+// NextContext is like Next but takes a context.Context for deadlines and cancellation.
+func (nm *NonceManager) NextContext(ctx context.Context, address string) (uint64, error) {
+	account := nm.account(address)
+
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	if !account.seeded {
+		pending, err := nm.pendingCount(ctx, address)
+		if err != nil {
+			return 0, err
+		}
+		account.next = pending
+		account.seeded = true
+	}
+
+	if len(account.released) > 0 {
+		nonce := account.released[0]
+		account.released = account.released[1:]
+		return nonce, nil
+	}
+
+	nonce := account.next
+	account.next++
+
+	return nonce, nil
+}
+
+// Release gives back a nonce whose transaction was not broadcast, e.g. because signing failed or the node rejected it.
+func (nm *NonceManager) Release(address string, nonce uint64) {
+	account := nm.account(address)
+
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	if !account.seeded || nonce >= account.next {
+		return
+	}
+
+	for _, released := range account.released {
+		if released == nonce {
+			return
+		}
+	}
+
+	account.released = append(account.released, nonce)
+	sort.Slice(account.released, func(i, j int) bool { return account.released[i] < account.released[j] })
+}
+
+// Resync discards the local state of the address and seeds it again from the pending transaction count.
+func (nm *NonceManager) Resync(address string) error {
+	return nm.ResyncContext(context.Background(), address)
+}
+
+// ResyncContext is like Resync but takes a context.Context for deadlines and cancellation.
+func (nm *NonceManager) ResyncContext(ctx context.Context, address string) error {
+	return nm.resync(ctx, address, true)
+}
+
+// resync seeds the account from the pending count. Unless forced, it never moves back behind nonces which were already
+// handed out, since their transactions may still be on their way to the node.
+func (nm *NonceManager) resync(ctx context.Context, address string, force bool) error {
+	account := nm.account(address)
+
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	pending, err := nm.pendingCount(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	if force || !account.seeded || pending > account.next {
+		account.next = pending
+	}
+	account.seeded = true
+
+	released := make([]uint64, 0, len(account.released))
+	for _, nonce := range account.released {
+		if !force && nonce >= pending && nonce < account.next {
+			released = append(released, nonce)
+		}
+	}
+	account.released = released
+
+	return nil
+}
+
+func (nm *NonceManager) pendingCount(ctx context.Context, address string) (uint64, error) {
+	count, err := nm.client.Eth.GetTransactionCountContext(ctx, address, rpctypes.QuantityPending())
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(count), nil
+}
+
+// IsNonceError reports whether the node rejected a transaction because its nonce was already used
+// ("nonce too low", "replacement transaction underpriced").
+func IsNonceError(err error) bool {
+	return rpcErrorContains(err, nonceTooLowMessages)
+}
+
+// IsAlreadyKnownError reports whether the node rejected a transaction because it already has it ("already known").
+func IsAlreadyKnownError(err error) bool {
+	return rpcErrorContains(err, alreadyKnownMessages)
+}
+
+func rpcErrorContains(err error, messages []string) bool {
+	rpcError, ok := err.(*RPCError)
+	if !ok {
+		return false
+	}
+
+	message := strings.ToLower(rpcError.Message)
+	for _, m := range messages {
+		if strings.Contains(message, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Send sets the next nonce of the key's address, signs the transaction and submits it with eth_sendRawTransaction.
+//
+// If the node reports that the nonce was already used, the account is resynced and the transaction is signed again
+// with a new nonce, up to 3 times. "already known" counts as success. If the node rejects the transaction for any other
+// reason, the nonce is released; after transport errors it is kept, since the transaction may have been broadcast.
+func (nm *NonceManager) Send(tx *RawTransaction, key *ecdsa.PrivateKey) (*SignedTransaction, error) {
+	return nm.SendContext(context.Background(), tx, key)
+}
+
+// SendContext is like Send but takes a context.Context for deadlines and cancellation.
+func (nm *NonceManager) SendContext(ctx context.Context, tx *RawTransaction, key *ecdsa.PrivateKey) (*SignedTransaction, error) {
+	if key == nil {
+		return nil, errors.New("private key cannot be nil")
+	}
+
+	address := strings.ToLower(crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	if err := nm.applyGasPrice(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		nonce, err := nm.NextContext(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+
+		tx.Nonce = nonce
+
+		signed, err := tx.Sign(key)
+		if err != nil {
+			nm.Release(address, nonce)
+			return nil, err
+		}
+
+		_, err = nm.client.Eth.SendSignedTransactionContext(ctx, signed)
+
+		switch {
+		case err == nil || IsAlreadyKnownError(err):
+			return signed, nil
+		case IsNonceError(err) && attempt < maxNonceAttempts:
+			if err := nm.resync(ctx, address, false); err != nil {
+				return nil, err
+			}
+		case IsNonceError(err):
+			nm.resync(ctx, address, false)
+			return nil, err
+		default:
+			if _, ok := err.(*RPCError); ok {
+				nm.Release(address, nonce)
+			}
+			return nil, err
+		}
+	}
+}
+
+// applyGasPrice sets the suggested gas prices if the transaction has none.
+func (nm *NonceManager) applyGasPrice(ctx context.Context, tx *RawTransaction) error {
+	if nm.GasStrategy == nil {
+		return nil
+	}
+
+	if tx.Type == rpctypes.TransactionTypeDynamicFee && tx.MaxFeePerGas != nil || tx.Type != rpctypes.TransactionTypeDynamicFee && tx.GasPrice != nil {
+		return nil
+	}
+
+	prices, err := nm.GasStrategy.SuggestGasPrices(ctx)
+	if err != nil {
+		return err
+	}
+
+	prices.Suggestion(nm.GasSpeed).Apply(tx)
+
+	return nil
+}
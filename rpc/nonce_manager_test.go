@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// nonceTestServer answers eth_getTransactionCount with the given counts one after the other (the last one repeatedly)
+// and eth_sendRawTransaction with the given errors, then with success.
+func nonceTestServer(counts []string, sendErrors []*RPCError) (*httptest.Server, *int32) {
+	countCalls, sendCalls := new(int32), new(int32)
+
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		switch request.Method {
+		case MethodGetTransactionCount:
+			call := int(atomic.AddInt32(countCalls, 1)) - 1
+			if call >= len(counts) {
+				call = len(counts) - 1
+			}
+			return RPCResponse{Result: counts[call]}
+		case MethodSendRawTransaction:
+			call := int(atomic.AddInt32(sendCalls, 1)) - 1
+			if call < len(sendErrors) {
+				return RPCResponse{Error: sendErrors[call]}
+			}
+			return RPCResponse{Result: "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"}
+		}
+		return RPCResponse{}
+	})
+
+	return server, countCalls
+}
+
+func TestNonceManager_Concurrent(t *testing.T) {
+	server, countCalls := nonceTestServer([]string{"0x7"}, nil)
+	defer server.Close()
+
+	nm := NewNonceManager(NewRPCClient(server.URL))
+	address := "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f"
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[uint64]bool)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := nm.Next(address)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			seen[nonce] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for nonce := uint64(7); nonce < 27; nonce++ {
+		if !seen[nonce] {
+			t.Errorf("nonce not handed out [Expected: %v, Actual: %v]", nonce, seen)
+			return
+		}
+	}
+
+	if *countCalls != 1 {
+		t.Errorf("wrong number of seeds [Expected: %v, Actual: %v]", 1, *countCalls)
+	}
+}
+
+func TestNonceManager_Release(t *testing.T) {
+	server, _ := nonceTestServer([]string{"0x0"}, nil)
+	defer server.Close()
+
+	nm := NewNonceManager(NewRPCClient(server.URL))
+	address := "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f"
+
+	for i := 0; i < 3; i++ {
+		nm.Next(address)
+	}
+	nm.Release(address, 1)
+
+	if nonce, _ := nm.Next(address); nonce != 1 {
+		t.Errorf("released nonce not reused [Expected: %v, Actual: %v]", 1, nonce)
+	}
+
+	if nonce, _ := nm.Next(address); nonce != 3 {
+		t.Errorf("wrong nonce [Expected: %v, Actual: %v]", 3, nonce)
+	}
+}
+
+func TestNonceManager_SendResync(t *testing.T) {
+	server, _ := nonceTestServer([]string{"0x3", "0x5"}, []*RPCError{{Code: -32000, Message: "nonce too low"}})
+	defer server.Close()
+
+	key, _ := crypto.HexToECDSA("4646464646464646464646464646464646464646464646464646464646464646")
+	nm := NewNonceManager(NewRPCClient(server.URL))
+
+	signed, err := nm.Send(eip155Transaction(), key)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if signed.Transaction.Nonce != 5 {
+		t.Errorf("nonce not resynced [Expected: %v, Actual: %v]", 5, signed.Transaction.Nonce)
+	}
+
+	if nonce, _ := nm.Next(signed.From.String()); nonce != 6 {
+		t.Errorf("wrong next nonce [Expected: %v, Actual: %v]", 6, nonce)
+	}
+}
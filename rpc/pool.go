@@ -0,0 +1,410 @@
+package rpc
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type PoolStrategy int
+
+const (
+	// PoolRoundRobin spreads requests evenly over all healthy nodes.
+	PoolRoundRobin PoolStrategy = iota
+	// PoolLatencyWeighted picks healthy nodes randomly, weighted by the inverse of their average latency.
+	PoolLatencyWeighted
+)
+
+// latencyWeight is the weight of a new latency sample in a node's moving average.
+const latencyWeight = 0.3
+
+// PoolConfig configures the node selection and health checks of a Pool.
+type PoolConfig struct {
+	Strategy PoolStrategy
+	// HealthCheckInterval is the time between two health checks (eth_blockNumber and eth_syncing) of all nodes.
+	// 0 disables health checks, nodes are then only judged by the outcome of regular requests.
+	HealthCheckInterval time.Duration
+	HealthCheckTimeout  time.Duration
+	// MaxBlockLag is the number of blocks a node may lag behind the best known head before it is avoided.
+	MaxBlockLag int64
+	// FailureThreshold is the number of consecutive failures after which a node's circuit opens.
+	// While the circuit is open, the node only receives requests if no other node is available.
+	FailureThreshold int
+	// CircuitOpenDuration is the time until a node with an open circuit is tried again.
+	CircuitOpenDuration time.Duration
+}
+
+// DefaultPoolConfig returns a round robin configuration with health checks every 15s,
+// a max lag of 5 blocks and a circuit that opens for 30s after 3 consecutive failures.
+func DefaultPoolConfig() *PoolConfig {
+	return &PoolConfig{
+		Strategy:            PoolRoundRobin,
+		HealthCheckInterval: 15 * time.Second,
+		HealthCheckTimeout:  5 * time.Second,
+		MaxBlockLag:         5,
+		FailureThreshold:    3,
+		CircuitOpenDuration: 30 * time.Second,
+	}
+}
+
+// PoolNodeStatus is a snapshot of what the pool knows about one of its nodes.
+type PoolNodeStatus struct {
+	Endpoint            string
+	Head                int64 // block number of the last health check, -1 if unknown
+	Latency             time.Duration
+	Syncing             bool
+	Lagging             bool
+	CircuitOpen         bool
+	ConsecutiveFailures int
+}
+
+type poolNode struct {
+	client *Client
+
+	mu                  sync.Mutex
+	head                int64
+	latency             time.Duration
+	syncing             bool
+	consecutiveFailures int
+	openUntil           time.Time
+}
+
+// Pool is a Transport that distributes requests over several clients, e.g. one per provider.
+//
+// Requests are sent to healthy nodes only: nodes whose circuit is closed, which are not syncing and which do not lag
+// more than MaxBlockLag blocks behind the best head of all nodes. If a node fails with a transport error or a
+// retryable rpc error (see IsRetryableError), the request is sent to the next healthy node.
+// If no node is healthy, all nodes are tried.
+//
+// Use it with NewRPCClientWithTransport to get the usual Eth, Net, Web3 and Personal modules on top of it.
+// Subscriptions are not supported by a Pool.
+type Pool struct {
+	config *PoolConfig
+	nodes  []*poolNode
+	next   uint32
+	quit   chan struct{}
+	once   sync.Once
+}
+
+// NewPool creates a Pool of the given clients and starts its health checks. A nil config uses DefaultPoolConfig().
+func NewPool(config *PoolConfig, clients ...*Client) *Pool {
+	if config == nil {
+		config = DefaultPoolConfig()
+	}
+
+	pool := &Pool{
+		config: config,
+		nodes:  make([]*poolNode, len(clients)),
+		quit:   make(chan struct{}),
+	}
+
+	for k, c := range clients {
+		pool.nodes[k] = &poolNode{client: c, head: -1}
+	}
+
+	if config.HealthCheckInterval > 0 {
+		go pool.healthCheckLoop()
+	}
+
+	return pool
+}
+
+// NewPoolClient is a shorthand for NewRPCClientWithTransport(NewPool(config, clients...)).
+func NewPoolClient(config *PoolConfig, clients ...*Client) *Client {
+	return NewRPCClientWithTransport(NewPool(config, clients...))
+}
+
+func (pool *Pool) Call(ctx context.Context, request *RPCRequest) (*RPCResponse, error) {
+	var response *RPCResponse
+
+	err := pool.try(ctx, func(node *poolNode) (error, error) {
+		var err error
+		response, err = node.client.call(ctx, request)
+		if err != nil {
+			return err, err
+		}
+		if response.Error != nil {
+			return response.Error, nil
+		}
+		return nil, nil
+	})
+
+	return response, err
+}
+
+func (pool *Pool) Notify(ctx context.Context, notification *RPCNotification) error {
+	return pool.try(ctx, func(node *poolNode) (error, error) {
+		err := node.client.notify(ctx, notification)
+		return err, err
+	})
+}
+
+func (pool *Pool) Batch(ctx context.Context, requests []interface{}) ([]RPCResponse, error) {
+	var responses []RPCResponse
+
+	err := pool.try(ctx, func(node *poolNode) (error, error) {
+		var err error
+		responses, err = node.client.batch(ctx, requests)
+		return err, err
+	})
+
+	return responses, err
+}
+
+// Close stops the health checks and closes all clients of the pool.
+func (pool *Pool) Close() error {
+	var err error
+
+	pool.once.Do(func() {
+		close(pool.quit)
+		for _, node := range pool.nodes {
+			if e := node.client.Close(); e != nil && err == nil {
+				err = e
+			}
+		}
+	})
+
+	return err
+}
+
+// Status returns a snapshot of the state of all nodes, in the order the clients were passed to NewPool.
+func (pool *Pool) Status() []PoolNodeStatus {
+	best := pool.bestHead()
+	now := time.Now()
+	status := make([]PoolNodeStatus, len(pool.nodes))
+
+	for k, node := range pool.nodes {
+		node.mu.Lock()
+		status[k] = PoolNodeStatus{
+			Endpoint:            node.client.endpoint,
+			Head:                node.head,
+			Latency:             node.latency,
+			Syncing:             node.syncing,
+			Lagging:             node.head >= 0 && best-node.head > pool.config.MaxBlockLag,
+			CircuitOpen:         now.Before(node.openUntil),
+			ConsecutiveFailures: node.consecutiveFailures,
+		}
+		node.mu.Unlock()
+	}
+
+	return status
+}
+
+// try sends a request to the selected nodes one after the other until one succeeds. send returns the error that
+// decides about failover (transport error or rpc error) and the error to hand to the caller.
+func (pool *Pool) try(ctx context.Context, send func(node *poolNode) (cause error, err error)) error {
+	var err error
+
+	for _, node := range pool.selectNodes() {
+		start := time.Now()
+		var cause error
+		cause, err = send(node)
+
+		if cause == nil || !IsRetryableError(cause) {
+			node.success(time.Since(start))
+			return err
+		}
+
+		node.failure(pool.config)
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	return err
+}
+
+// selectNodes returns all nodes in the order they should be tried: healthy nodes first, ordered by the strategy.
+func (pool *Pool) selectNodes() []*poolNode {
+	best := pool.bestHead()
+	now := time.Now()
+
+	healthy := make([]*poolNode, 0, len(pool.nodes))
+	unhealthy := make([]*poolNode, 0)
+
+	for _, node := range pool.nodes {
+		if node.healthy(now, best, pool.config.MaxBlockLag) {
+			healthy = append(healthy, node)
+		} else {
+			unhealthy = append(unhealthy, node)
+		}
+	}
+
+	switch pool.config.Strategy {
+	case PoolLatencyWeighted:
+		healthy = orderByLatency(healthy)
+	default:
+		healthy = rotate(healthy, int(atomic.AddUint32(&pool.next, 1)-1))
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+func (pool *Pool) bestHead() int64 {
+	best := int64(-1)
+
+	for _, node := range pool.nodes {
+		node.mu.Lock()
+		if !node.syncing && node.head > best {
+			best = node.head
+		}
+		node.mu.Unlock()
+	}
+
+	return best
+}
+
+func (pool *Pool) healthCheckLoop() {
+	ticker := time.NewTicker(pool.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		pool.checkHealth()
+
+		select {
+		case <-ticker.C:
+		case <-pool.quit:
+			return
+		}
+	}
+}
+
+// checkHealth requests head and sync state of all nodes concurrently.
+func (pool *Pool) checkHealth() {
+	var wg sync.WaitGroup
+
+	for _, node := range pool.nodes {
+		wg.Add(1)
+		go func(node *poolNode) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), pool.config.HealthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			head, err := node.client.Eth.BlockNumberContext(ctx)
+			if err != nil {
+				node.failure(pool.config)
+				return
+			}
+			latency := time.Since(start)
+
+			status, err := node.client.Eth.SyncingContext(ctx)
+			if err != nil {
+				node.failure(pool.config)
+				return
+			}
+
+			node.mu.Lock()
+			node.head = head
+			node.syncing = status.IsSyncing
+			node.mu.Unlock()
+
+			node.success(latency)
+		}(node)
+	}
+
+	wg.Wait()
+}
+
+func (node *poolNode) healthy(now time.Time, bestHead int64, maxBlockLag int64) bool {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+
+	if now.Before(node.openUntil) || node.syncing {
+		return false
+	}
+
+	return node.head < 0 || bestHead-node.head <= maxBlockLag
+}
+
+func (node *poolNode) success(latency time.Duration) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+
+	node.consecutiveFailures = 0
+	node.openUntil = time.Time{}
+
+	if node.latency == 0 {
+		node.latency = latency
+	} else {
+		node.latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(node.latency))
+	}
+}
+
+func (node *poolNode) failure(config *PoolConfig) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+
+	node.consecutiveFailures++
+
+	if config.FailureThreshold > 0 && node.consecutiveFailures >= config.FailureThreshold {
+		node.openUntil = time.Now().Add(config.CircuitOpenDuration)
+	}
+}
+
+func rotate(nodes []*poolNode, n int) []*poolNode {
+	if len(nodes) == 0 {
+		return nodes
+	}
+
+	n = n % len(nodes)
+	return append(nodes[n:], nodes[:n]...)
+}
+
+// orderByLatency draws nodes without replacement, each with a probability proportional to 1/latency.
+// Nodes without latency measurement are treated as fast as the fastest known node.
+func orderByLatency(nodes []*poolNode) []*poolNode {
+	weights := make([]float64, len(nodes))
+	fastest := time.Duration(0)
+
+	for k, node := range nodes {
+		node.mu.Lock()
+		weights[k] = float64(node.latency)
+		node.mu.Unlock()
+
+		if latency := time.Duration(weights[k]); latency > 0 && (fastest == 0 || latency < fastest) {
+			fastest = latency
+		}
+	}
+
+	for k := range weights {
+		if weights[k] == 0 {
+			weights[k] = float64(fastest)
+		}
+		if weights[k] == 0 {
+			weights[k] = 1
+		} else {
+			weights[k] = 1 / weights[k]
+		}
+	}
+
+	remaining := append([]*poolNode{}, nodes...)
+	ordered := make([]*poolNode, 0, len(nodes))
+
+	for len(remaining) > 0 {
+		total := 0.0
+		for _, w := range weights {
+			total += w
+		}
+
+		pick := len(remaining) - 1
+		r := rand.Float64() * total
+		for k, w := range weights {
+			if r < w {
+				pick = k
+				break
+			}
+			r -= w
+		}
+
+		ordered = append(ordered, remaining[pick])
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+		weights = append(weights[:pick], weights[pick+1:]...)
+	}
+
+	return ordered
+}
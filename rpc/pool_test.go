@@ -0,0 +1,137 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// poolTestServer answers eth_blockNumber with head, eth_syncing with false and counts all other requests.
+func poolTestServer(head string, fail bool) (*httptest.Server, *int32) {
+	calls := new(int32)
+
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		if fail {
+			return RPCResponse{Result: httpStatus(http.StatusBadGateway)}
+		}
+
+		switch request.Method {
+		case MethodEthBlockNumber:
+			return RPCResponse{Result: head}
+		case MethodSyncing:
+			return RPCResponse{Result: false}
+		}
+
+		atomic.AddInt32(calls, 1)
+		return RPCResponse{Result: "0x1"}
+	})
+
+	return server, calls
+}
+
+func testPoolConfig() *PoolConfig {
+	config := DefaultPoolConfig()
+	config.HealthCheckInterval = 0
+	return config
+}
+
+func TestPool_RoundRobin(t *testing.T) {
+	first, firstCalls := poolTestServer("0x64", false)
+	defer first.Close()
+	second, secondCalls := poolTestServer("0x64", false)
+	defer second.Close()
+
+	client := NewPoolClient(testPoolConfig(), NewRPCClient(first.URL), NewRPCClient(second.URL))
+	defer client.Close()
+
+	for i := 0; i < 10; i++ {
+		if _, err := client.Eth.GasPrice(); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if *firstCalls != 5 || *secondCalls != 5 {
+		t.Errorf("requests not balanced [Expected: %v/%v, Actual: %v/%v]", 5, 5, *firstCalls, *secondCalls)
+	}
+}
+
+func TestPool_Failover(t *testing.T) {
+	broken, _ := poolTestServer("0x64", true)
+	defer broken.Close()
+	healthy, healthyCalls := poolTestServer("0x64", false)
+	defer healthy.Close()
+
+	pool := NewPool(testPoolConfig(), NewRPCClient(broken.URL), NewRPCClient(healthy.URL))
+	client := NewRPCClientWithTransport(pool)
+	defer client.Close()
+
+	for i := 0; i < 6; i++ {
+		if _, err := client.Eth.GasPrice(); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if *healthyCalls != 6 {
+		t.Errorf("wrong number of requests on healthy node [Expected: %v, Actual: %v]", 6, *healthyCalls)
+	}
+
+	if status := pool.Status(); !status[0].CircuitOpen {
+		t.Errorf("circuit of broken node not open [Expected: %v, Actual: %v]", true, status[0].CircuitOpen)
+	}
+}
+
+func TestPool_NotifyInterceptors(t *testing.T) {
+	server, _ := poolTestServer("0x64", false)
+	defer server.Close()
+
+	notified := make([]string, 0)
+	node := NewRPCClient(server.URL)
+	node.Use(InterceptorFuncs{Notify: func(ctx context.Context, notification *RPCNotification, next NotifyFunc) error {
+		notified = append(notified, notification.Method)
+		return next(ctx, notification)
+	}})
+
+	client := NewPoolClient(testPoolConfig(), node)
+	defer client.Close()
+
+	if err := client.Notification(MethodEthBlockNumber); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(notified) != 1 || notified[0] != MethodEthBlockNumber {
+		t.Errorf("notification bypassed the node interceptors [Expected: %v, Actual: %v]", []string{MethodEthBlockNumber}, notified)
+	}
+}
+
+func TestPool_AvoidLaggingNodes(t *testing.T) {
+	lagging, laggingCalls := poolTestServer("0x5a", false)
+	defer lagging.Close()
+	synced, syncedCalls := poolTestServer("0x64", false)
+	defer synced.Close()
+
+	pool := NewPool(testPoolConfig(), NewRPCClient(lagging.URL), NewRPCClient(synced.URL))
+	defer pool.Close()
+	pool.checkHealth()
+
+	client := NewRPCClientWithTransport(pool)
+
+	for i := 0; i < 4; i++ {
+		if _, err := client.Eth.GasPrice(); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if *laggingCalls != 0 || *syncedCalls != 4 {
+		t.Errorf("lagging node not avoided [Expected: %v/%v, Actual: %v/%v]", 0, 4, *laggingCalls, *syncedCalls)
+	}
+
+	if status := pool.Status(); !status[0].Lagging || status[1].Head != 100 {
+		t.Errorf("wrong node status [Expected: lagging and head %v, Actual: %v and head %v]", 100, status[0].Lagging, status[1].Head)
+	}
+}
@@ -0,0 +1,236 @@
+package rpc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/Leondroids/go-ethereum-rpc/rpcutils"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// VerifyAccountProof checks the result of eth_getProof against a trusted state root, e.g. the StateRoot of a block
+// header obtained from a source you trust. It verifies that the account fields are the ones stored in the state trie
+// (or that the account does not exist, in which case they must be empty) and that every storage proof matches the
+// storage root of the account.
+func VerifyAccountProof(stateRoot *rpctypes.HexString, proof *rpctypes.AccountProof) error {
+	if stateRoot == nil || proof == nil {
+		return errors.New("state root and proof cannot be nil")
+	}
+
+	value, err := verifyMerkleProof(stateRoot.Bytes(), proof.Address.Bytes(), proof.AccountProof)
+	if err != nil {
+		return fmt.Errorf("invalid account proof of %v: %v", proof.Address.String(), err)
+	}
+
+	if err := checkAccount(value, proof); err != nil {
+		return fmt.Errorf("invalid account proof of %v: %v", proof.Address.String(), err)
+	}
+
+	for _, storage := range proof.StorageProof {
+		if len(storage.Key.Bytes()) > 32 {
+			return fmt.Errorf("invalid storage proof of %v: key is longer than 32 bytes", storage.Key.String())
+		}
+		key := make([]byte, 32)
+		copy(key[32-len(storage.Key.Bytes()):], storage.Key.Bytes())
+
+		value, err := verifyMerkleProof(proof.StorageHash.Bytes(), key, storage.Proof)
+		if err != nil {
+			return fmt.Errorf("invalid storage proof of %v: %v", storage.Key.String(), err)
+		}
+
+		stored := new(big.Int)
+		if value != nil {
+			decoded, err := rpcutils.DecodeRLP(value)
+			if err != nil {
+				return fmt.Errorf("invalid storage proof of %v: %v", storage.Key.String(), err)
+			}
+			b, ok := decoded.([]byte)
+			if !ok {
+				return fmt.Errorf("invalid storage proof of %v: value is no byte string", storage.Key.String())
+			}
+			stored.SetBytes(b)
+		}
+
+		if storage.Value == nil || stored.Cmp(storage.Value) != 0 {
+			return fmt.Errorf("invalid storage proof of %v: proven value %v, reported %v", storage.Key.String(), stored, storage.Value)
+		}
+	}
+
+	return nil
+}
+
+// checkAccount compares the rlp encoded account [nonce, balance, storageRoot, codeHash] of the state trie
+// with the fields of the proof. value is nil if the account does not exist.
+func checkAccount(value []byte, proof *rpctypes.AccountProof) error {
+	nonce, balance := new(big.Int), new(big.Int)
+	storageHash, _ := rpctypes.NewHexString(rpctypes.EmptyRootHash)
+	codeHash, _ := rpctypes.NewHexString(rpctypes.EmptyCodeHash)
+
+	if value != nil {
+		decoded, err := rpcutils.DecodeRLP(value)
+		if err != nil {
+			return err
+		}
+
+		fields, ok := decoded.([]interface{})
+		if !ok || len(fields) != 4 {
+			return errors.New("account is no list of 4 fields")
+		}
+
+		raw := make([][]byte, 4)
+		for k, field := range fields {
+			if raw[k], ok = field.([]byte); !ok {
+				return fmt.Errorf("account field %v is no byte string", k)
+			}
+		}
+
+		nonce.SetBytes(raw[0])
+		balance.SetBytes(raw[1])
+		storageHash = rpctypes.NewHexStringFromBytes(raw[2])
+		codeHash = rpctypes.NewHexStringFromBytes(raw[3])
+	} else if proof.StorageHash.BigInt().Sign() == 0 && proof.CodeHash.BigInt().Sign() == 0 {
+		// some clients report zero hashes for accounts which do not exist
+		storageHash, codeHash = &proof.StorageHash, &proof.CodeHash
+	}
+
+	switch {
+	case nonce.Uint64() != proof.Nonce || !nonce.IsUint64():
+		return fmt.Errorf("proven nonce %v, reported %v", nonce, proof.Nonce)
+	case proof.Balance == nil || balance.Cmp(proof.Balance) != 0:
+		return fmt.Errorf("proven balance %v, reported %v", balance, proof.Balance)
+	case !bytes.Equal(storageHash.Bytes(), proof.StorageHash.Bytes()):
+		return fmt.Errorf("proven storage hash %v, reported %v", storageHash.String(), proof.StorageHash.String())
+	case !bytes.Equal(codeHash.Bytes(), proof.CodeHash.Bytes()):
+		return fmt.Errorf("proven code hash %v, reported %v", codeHash.String(), proof.CodeHash.String())
+	}
+
+	return nil
+}
+
+// verifyMerkleProof walks the proof nodes of a secure merkle patricia trie (keys are hashed with keccak256) from the
+// root to key and returns the stored value, or nil if the proof shows that the key is not in the trie.
+// The empty trie has no nodes, every key is absent and nodes return an empty proof.
+func verifyMerkleProof(root []byte, key []byte, proof []rpctypes.HexString) ([]byte, error) {
+	emptyRoot, _ := rpctypes.NewHexString(rpctypes.EmptyRootHash)
+	if len(proof) == 0 && bytes.Equal(root, emptyRoot.Bytes()) {
+		return nil, nil
+	}
+
+	path := keyNibbles(crypto.Keccak256(key))
+	hash := root
+
+	for k, node := range proof {
+		encoded := node.Bytes()
+		if !bytes.Equal(crypto.Keccak256(encoded), hash) {
+			return nil, fmt.Errorf("node %v does not match the hash %x", k, hash)
+		}
+
+		decoded, err := rpcutils.DecodeRLP(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("node %v: %v", k, err)
+		}
+
+		var value []byte
+		value, hash, path, err = walkTrieNode(decoded, path)
+		if err != nil {
+			return nil, fmt.Errorf("node %v: %v", k, err)
+		}
+
+		if hash == nil {
+			return value, nil
+		}
+	}
+
+	return nil, errors.New("proof ends before the key is reached")
+}
+
+// walkTrieNode follows path through the node and the nodes embedded in it. It returns either the value at the end of
+// the path (nil if the path leads nowhere) or the hash of the next node with the remaining path.
+func walkTrieNode(node interface{}, path []byte) ([]byte, []byte, []byte, error) {
+	for {
+		items, ok := node.([]interface{})
+		if !ok {
+			return nil, nil, nil, errors.New("trie node is no list")
+		}
+
+		var child interface{}
+
+		switch len(items) {
+		case 17: // branch
+			if len(path) == 0 {
+				value, ok := items[16].([]byte)
+				if !ok {
+					return nil, nil, nil, errors.New("branch value is no byte string")
+				}
+				if len(value) == 0 {
+					return nil, nil, nil, nil
+				}
+				return value, nil, nil, nil
+			}
+			child = items[path[0]]
+			path = path[1:]
+		case 2: // extension or leaf
+			compact, ok := items[0].([]byte)
+			if !ok || len(compact) == 0 {
+				return nil, nil, nil, errors.New("invalid node path")
+			}
+			nibbles, leaf := compactToNibbles(compact)
+			if leaf {
+				if !bytes.Equal(nibbles, path) {
+					return nil, nil, nil, nil
+				}
+				value, ok := items[1].([]byte)
+				if !ok {
+					return nil, nil, nil, errors.New("leaf value is no byte string")
+				}
+				return value, nil, nil, nil
+			}
+			if len(path) < len(nibbles) || !bytes.Equal(nibbles, path[:len(nibbles)]) {
+				return nil, nil, nil, nil
+			}
+			child = items[1]
+			path = path[len(nibbles):]
+		default:
+			return nil, nil, nil, fmt.Errorf("trie node with %v items", len(items))
+		}
+
+		switch c := child.(type) {
+		case []byte:
+			switch len(c) {
+			case 0:
+				return nil, nil, nil, nil
+			case 32:
+				return nil, c, path, nil
+			default:
+				return nil, nil, nil, fmt.Errorf("child reference of %v bytes", len(c))
+			}
+		default:
+			node = c // nodes shorter than 32 bytes are embedded in their parent
+		}
+	}
+}
+
+func keyNibbles(key []byte) []byte {
+	nibbles := make([]byte, 2*len(key))
+	for k, b := range key {
+		nibbles[2*k] = b >> 4
+		nibbles[2*k+1] = b & 0x0f
+	}
+
+	return nibbles
+}
+
+// compactToNibbles decodes the hex prefix encoding of a leaf or extension path.
+func compactToNibbles(compact []byte) ([]byte, bool) {
+	flag := compact[0] >> 4
+	nibbles := keyNibbles(compact)[2:]
+
+	if flag&1 == 1 {
+		nibbles = append([]byte{compact[0] & 0x0f}, nibbles...)
+	}
+
+	return nibbles, flag&2 == 2
+}
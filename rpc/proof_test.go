@@ -0,0 +1,233 @@
+package rpc
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/Leondroids/go-ethereum-rpc/rpcutils"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func mustRLP(value interface{}) []byte {
+	encoded, err := rpcutils.EncodeRLP(value)
+	if err != nil {
+		panic(err)
+	}
+	return encoded
+}
+
+// testLeaf returns the rlp encoded leaf node with the path after skip nibbles of keccak256(key).
+func testLeaf(key []byte, skip int, value []byte) []byte {
+	nibbles := keyNibbles(crypto.Keccak256(key))[skip:]
+
+	compact := []byte{0x20}
+	if len(nibbles)%2 == 1 {
+		compact = []byte{0x30 | nibbles[0]}
+		nibbles = nibbles[1:]
+	}
+	for i := 0; i < len(nibbles); i += 2 {
+		compact = append(compact, nibbles[i]<<4|nibbles[i+1])
+	}
+
+	return mustRLP([]interface{}{compact, value})
+}
+
+func testSlotKey(slot int64) []byte {
+	key := make([]byte, 32)
+	return append(key[:32-len(big.NewInt(slot).Bytes())], big.NewInt(slot).Bytes()...)
+}
+
+func hexStrings(nodes ...[]byte) []rpctypes.HexString {
+	list := make([]rpctypes.HexString, len(nodes))
+	for k, node := range nodes {
+		list[k] = *rpctypes.NewHexStringFromBytes(node)
+	}
+	return list
+}
+
+// testAccountProof builds a state trie with a single account whose storage trie holds slot 0 = 7 and slot 1 = 0x0100
+// in a branch node (the hashed keys start with the nibbles 2 and b). It returns the state root and the proof of the account
+// and the slots 0, 1 and the missing slot 2.
+func testAccountProof() (*rpctypes.HexString, *rpctypes.AccountProof) {
+	leaf0 := testLeaf(testSlotKey(0), 1, mustRLP(big.NewInt(7)))
+	leaf1 := testLeaf(testSlotKey(1), 1, mustRLP(big.NewInt(0x0100)))
+
+	children := make([]interface{}, 17)
+	for k := range children {
+		children[k] = []byte{}
+	}
+	children[keyNibbles(crypto.Keccak256(testSlotKey(0)))[0]] = crypto.Keccak256(leaf0)
+	children[keyNibbles(crypto.Keccak256(testSlotKey(1)))[0]] = crypto.Keccak256(leaf1)
+	branch := mustRLP(children)
+	storageRoot := crypto.Keccak256(branch)
+
+	codeHash := crypto.Keccak256([]byte{0x60, 0x00})
+	address, _ := new(rpctypes.EtherAddress).FromString("0x7f0d15c7faae65896648c8273b6d7e43f58fa842")
+	account := mustRLP([]interface{}{uint64(5), big.NewInt(1000000), storageRoot, codeHash})
+	accountLeaf := testLeaf(address.Bytes(), 0, account)
+
+	return rpctypes.NewHexStringFromBytes(crypto.Keccak256(accountLeaf)), &rpctypes.AccountProof{
+		Address:      *address,
+		Balance:      big.NewInt(1000000),
+		Nonce:        5,
+		CodeHash:     *rpctypes.NewHexStringFromBytes(codeHash),
+		StorageHash:  *rpctypes.NewHexStringFromBytes(storageRoot),
+		AccountProof: hexStrings(accountLeaf),
+		StorageProof: []rpctypes.StorageProof{
+			{Key: *rpctypes.NewHexStringFromBytes([]byte{0x00}), Value: big.NewInt(7), Proof: hexStrings(branch, leaf0)},
+			{Key: *rpctypes.NewHexStringFromBytes([]byte{0x01}), Value: big.NewInt(0x0100), Proof: hexStrings(branch, leaf1)},
+			{Key: *rpctypes.NewHexStringFromBytes([]byte{0x02}), Value: big.NewInt(0), Proof: hexStrings(branch)},
+		},
+	}
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	root, proof := testAccountProof()
+
+	if err := VerifyAccountProof(root, proof); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !proof.HasCode() {
+		t.Errorf("account has code [Expected: %v, Actual: %v]", true, proof.HasCode())
+	}
+}
+
+func TestVerifyAccountProof_Tampered(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(root *rpctypes.HexString, proof *rpctypes.AccountProof)
+		err    string
+	}{
+		{"balance", func(root *rpctypes.HexString, proof *rpctypes.AccountProof) { proof.Balance = big.NewInt(1000001) }, "balance"},
+		{"nonce", func(root *rpctypes.HexString, proof *rpctypes.AccountProof) { proof.Nonce = 4 }, "nonce"},
+		{"storage value", func(root *rpctypes.HexString, proof *rpctypes.AccountProof) { proof.StorageProof[0].Value = big.NewInt(8) }, "storage"},
+		{"missing slot", func(root *rpctypes.HexString, proof *rpctypes.AccountProof) { proof.StorageProof[2].Value = big.NewInt(1) }, "storage"},
+		{"state root", func(root *rpctypes.HexString, proof *rpctypes.AccountProof) { root.FromBytes(crypto.Keccak256([]byte("other"))) }, "hash"},
+		{"incomplete", func(root *rpctypes.HexString, proof *rpctypes.AccountProof) { proof.StorageProof[0].Proof = proof.StorageProof[0].Proof[:1] }, "ends"},
+		{"long key", func(root *rpctypes.HexString, proof *rpctypes.AccountProof) { proof.StorageProof[0].Key.FromBytes(make([]byte, 33)) }, "longer than 32 bytes"},
+	}
+
+	for _, test := range tests {
+		root, proof := testAccountProof()
+		test.tamper(root, proof)
+
+		if err := VerifyAccountProof(root, proof); err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%v not detected [Expected: %v, Actual: %v]", test.name, test.err, err)
+		}
+	}
+}
+
+func TestVerifyAccountProof_MissingAccount(t *testing.T) {
+	root, existing := testAccountProof()
+
+	address, _ := new(rpctypes.EtherAddress).FromString("0x0000000000000000000000000000000000000001")
+	storageHash, _ := rpctypes.NewHexString(rpctypes.EmptyRootHash)
+	codeHash, _ := rpctypes.NewHexString(rpctypes.EmptyCodeHash)
+
+	proof := &rpctypes.AccountProof{
+		Address:      *address,
+		Balance:      big.NewInt(0),
+		StorageHash:  *storageHash,
+		CodeHash:     *codeHash,
+		AccountProof: existing.AccountProof,
+	}
+
+	if err := VerifyAccountProof(root, proof); err != nil {
+		t.Error(err)
+		return
+	}
+
+	proof.Balance = big.NewInt(1)
+	if err := VerifyAccountProof(root, proof); err == nil {
+		t.Errorf("balance of missing account accepted [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestVerifyAccountProof_EmptyStorage(t *testing.T) {
+	root, existing := testAccountProof()
+
+	address, _ := new(rpctypes.EtherAddress).FromString("0x0000000000000000000000000000000000000001")
+	storageHash, _ := rpctypes.NewHexString(rpctypes.EmptyRootHash)
+	codeHash, _ := rpctypes.NewHexString(rpctypes.EmptyCodeHash)
+	slot, _ := rpctypes.NewHexString("0x00")
+
+	// geth proves slots of an empty storage trie with an empty proof and value 0
+	proof := &rpctypes.AccountProof{
+		Address:      *address,
+		Balance:      big.NewInt(0),
+		StorageHash:  *storageHash,
+		CodeHash:     *codeHash,
+		AccountProof: existing.AccountProof,
+		StorageProof: []rpctypes.StorageProof{{Key: *slot, Value: big.NewInt(0), Proof: []rpctypes.HexString{}}},
+	}
+
+	if err := VerifyAccountProof(root, proof); err != nil {
+		t.Error(err)
+		return
+	}
+
+	proof.StorageProof[0].Value = big.NewInt(1)
+	if err := VerifyAccountProof(root, proof); err == nil {
+		t.Errorf("value of empty storage accepted [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestGetProofParams(t *testing.T) {
+	var params interface{}
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		params = request.Params
+		return RPCResponse{Result: map[string]interface{}{
+			"address":      "0x7f0d15c7faae65896648c8273b6d7e43f58fa842",
+			"balance":      "0x0",
+			"nonce":        "0x0",
+			"codeHash":     rpctypes.EmptyCodeHash,
+			"storageHash":  rpctypes.EmptyRootHash,
+			"accountProof": []string{"0xf8718080"},
+			"storageProof": []interface{}{map[string]interface{}{"key": "0x1", "value": "0x0", "proof": []string{}}},
+		}}
+	})
+	defer server.Close()
+
+	proof, err := NewRPCClient(server.URL).Eth.GetProof("0x7f0d15c7faae65896648c8273b6d7e43f58fa842", []string{"0x1"}, rpctypes.QuantityBlock(16))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	js, _ := json.Marshal(params)
+	if expected := `["0x7f0d15c7faae65896648c8273b6d7e43f58fa842",["0x1"],"0x10"]`; string(js) != expected {
+		t.Errorf("wrong params [Expected: %v, Actual: %v]", expected, string(js))
+	}
+
+	if proof.HasCode() || len(proof.AccountProof) != 1 || len(proof.StorageProof) != 1 || proof.StorageProof[0].Key.Int64() != 1 {
+		t.Errorf("wrong proof [Expected: %v, Actual: %v]", "eoa with 1 account node and 1 storage proof", proof)
+	}
+}
+
+func TestIsContract(t *testing.T) {
+	codes := map[string]string{"0x0000000000000000000000000000000000000001": "0x", "0x0000000000000000000000000000000000000002": "0x6000"}
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		address := request.Params.([]interface{})[0].(string)
+		return RPCResponse{Result: codes[address]}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+
+	for address, code := range codes {
+		isContract, err := client.Eth.IsContract(address, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if expected := code != "0x"; isContract != expected {
+			t.Errorf("wrong result for %v [Expected: %v, Actual: %v]", address, expected, isContract)
+		}
+	}
+}
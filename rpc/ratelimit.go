@@ -0,0 +1,304 @@
+package rpc
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RateLimit limits the requests a Client sends, either in total (SetRateLimit) or per rpc method (SetMethodRateLimit).
+//
+// RequestsPerSecond and Burst configure a token bucket: every request of a call, batch or notification takes a token,
+// the bucket holds at most Burst tokens and is refilled with RequestsPerSecond tokens per second.
+// MaxInFlight caps the number of calls, batches and notifications waiting for the transport at the same time.
+// Zero values disable the respective limit.
+//
+// By default requests wait until they are allowed to pass (or their context is done). With FailFast set,
+// they fail immediately with RateLimited instead.
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int // defaults to RequestsPerSecond, rounded up
+	MaxInFlight       int
+	FailFast          bool
+}
+
+// RateLimitStats are the counters of a rate limit since it was set.
+type RateLimitStats struct {
+	Requests int64 // requests which passed the limit
+	Rejected int64 // requests which failed with RateLimited
+	Waited   int64 // requests which had to wait for a token or a free slot
+	WaitTime time.Duration
+	InFlight int
+}
+
+type limiter struct {
+	limit RateLimit
+	slots chan struct{}
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	stats  RateLimitStats
+}
+
+type rateLimits struct {
+	mu      sync.RWMutex
+	client  *limiter
+	methods map[string]*limiter
+}
+
+// SetRateLimit limits all requests of the client. A nil limit removes the limit.
+func (client *Client) SetRateLimit(limit *RateLimit) {
+	client.limits.mu.Lock()
+	defer client.limits.mu.Unlock()
+
+	client.limits.client = newLimiter(limit)
+}
+
+// SetMethodRateLimit limits the requests of a single rpc method, e.g. MethodGetLogs, in addition to the limit of the client.
+// A nil limit removes the limit of the method.
+func (client *Client) SetMethodRateLimit(method string, limit *RateLimit) {
+	client.limits.mu.Lock()
+	defer client.limits.mu.Unlock()
+
+	if client.limits.methods == nil {
+		client.limits.methods = make(map[string]*limiter)
+	}
+
+	if limit == nil {
+		delete(client.limits.methods, method)
+		return
+	}
+
+	client.limits.methods[method] = newLimiter(limit)
+}
+
+// RateLimitStats returns the counters of the limit set by SetRateLimit.
+func (client *Client) RateLimitStats() RateLimitStats {
+	client.limits.mu.RLock()
+	defer client.limits.mu.RUnlock()
+
+	return client.limits.client.snapshot()
+}
+
+// MethodRateLimitStats returns the counters of the limit set by SetMethodRateLimit for the given method.
+func (client *Client) MethodRateLimitStats(method string) RateLimitStats {
+	client.limits.mu.RLock()
+	defer client.limits.mu.RUnlock()
+
+	return client.limits.methods[method].snapshot()
+}
+
+// acquire blocks until requests of the given methods may be sent. The returned function must be called once the
+// requests are done.
+func (limits *rateLimits) acquire(ctx context.Context, methods ...string) (func(), error) {
+	limits.mu.RLock()
+	acquire := make([]*limiter, 0)
+	counts := make([]int, 0)
+
+	if limits.client != nil {
+		acquire = append(acquire, limits.client)
+		counts = append(counts, len(methods))
+	}
+
+	if len(limits.methods) != 0 {
+		perMethod := make(map[string]int)
+		for _, method := range methods {
+			if _, ok := limits.methods[method]; ok {
+				perMethod[method]++
+			}
+		}
+
+		// a fixed order keeps batches from deadlocking each other on the in-flight slots
+		names := make([]string, 0, len(perMethod))
+		for method := range perMethod {
+			names = append(names, method)
+		}
+		sort.Strings(names)
+
+		for _, method := range names {
+			acquire = append(acquire, limits.methods[method])
+			counts = append(counts, perMethod[method])
+		}
+	}
+	limits.mu.RUnlock()
+
+	release := func(acquired []*limiter) {
+		for _, l := range acquired {
+			l.release()
+		}
+	}
+
+	for k, l := range acquire {
+		if err := l.acquire(ctx, counts[k]); err != nil {
+			// the requests are not sent, earlier limiters get their tokens back and do not count them
+			for j, acquired := range acquire[:k] {
+				acquired.abort(counts[j])
+			}
+			return nil, err
+		}
+	}
+
+	return func() { release(acquire) }, nil
+}
+
+func newLimiter(limit *RateLimit) *limiter {
+	if limit == nil {
+		return nil
+	}
+
+	l := &limiter{limit: *limit, last: time.Now()}
+
+	if l.limit.Burst < 1 {
+		l.limit.Burst = int(math.Max(1, math.Ceil(l.limit.RequestsPerSecond)))
+	}
+	l.tokens = float64(l.limit.Burst)
+
+	if l.limit.MaxInFlight > 0 {
+		l.slots = make(chan struct{}, l.limit.MaxInFlight)
+	}
+
+	return l
+}
+
+// acquire takes n tokens and a slot.
+func (l *limiter) acquire(ctx context.Context, n int) error {
+	start := time.Now()
+
+	delay, err := l.take(start, n)
+	if err != nil {
+		return err
+	}
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			l.giveBack(n)
+			return ctx.Err()
+		}
+	}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		default:
+			if l.limit.FailFast {
+				l.giveBack(n)
+				l.reject(n)
+				return RateLimited
+			}
+
+			select {
+			case l.slots <- struct{}{}:
+			case <-ctx.Done():
+				l.giveBack(n)
+				return ctx.Err()
+			}
+		}
+	}
+
+	waited := time.Since(start)
+
+	l.mu.Lock()
+	l.stats.Requests += int64(n)
+	if delay > 0 || waited > time.Millisecond {
+		l.stats.Waited += int64(n)
+		l.stats.WaitTime += waited
+	}
+	l.mu.Unlock()
+
+	return nil
+}
+
+// take removes n tokens from the bucket and returns how long to wait until they are refilled.
+// Requests larger than the bucket take all tokens and wait for the remainder.
+func (l *limiter) take(now time.Time, n int) (time.Duration, error) {
+	if l.limit.RequestsPerSecond <= 0 {
+		return 0, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = math.Min(float64(l.limit.Burst), l.tokens+now.Sub(l.last).Seconds()*l.limit.RequestsPerSecond)
+	l.last = now
+
+	if l.limit.FailFast && l.tokens < math.Min(float64(n), float64(l.limit.Burst)) {
+		l.stats.Rejected += int64(n)
+		return 0, RateLimited
+	}
+
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0, nil
+	}
+
+	return time.Duration(-l.tokens / l.limit.RequestsPerSecond * float64(time.Second)), nil
+}
+
+func (l *limiter) giveBack(n int) {
+	if l.limit.RequestsPerSecond <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	l.tokens += float64(n)
+	l.mu.Unlock()
+}
+
+func (l *limiter) reject(n int) {
+	l.mu.Lock()
+	l.stats.Rejected += int64(n)
+	l.mu.Unlock()
+}
+
+// abort undoes a successful acquire of n requests which are not sent.
+func (l *limiter) abort(n int) {
+	l.giveBack(n)
+
+	l.mu.Lock()
+	l.stats.Requests -= int64(n)
+	l.mu.Unlock()
+
+	l.release()
+}
+
+func (l *limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+func (l *limiter) snapshot() RateLimitStats {
+	if l == nil {
+		return RateLimitStats{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.stats
+	stats.InFlight = len(l.slots)
+	return stats
+}
+
+// requestMethods returns the method of every request and notification of a batch.
+func requestMethods(requests []interface{}) []string {
+	methods := make([]string, 0, len(requests))
+
+	for _, r := range requests {
+		switch r := r.(type) {
+		case *RPCRequest:
+			methods = append(methods, r.Method)
+		case *RPCNotification:
+			methods = append(methods, r.Method)
+		}
+	}
+
+	return methods
+}
@@ -0,0 +1,156 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_RateLimitBlocking(t *testing.T) {
+	server, attempts := testServer(func(request RPCRequest) RPCResponse {
+		return RPCResponse{Result: "0x1"}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.SetRateLimit(&RateLimit{RequestsPerSecond: 50, Burst: 1})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if _, err := client.Eth.BlockNumber(); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("requests not throttled [Expected: >= %v, Actual: %v]", 100*time.Millisecond, elapsed)
+	}
+
+	stats := client.RateLimitStats()
+	if stats.Requests != 6 || stats.Waited != 5 || *attempts != 6 {
+		t.Errorf("wrong stats [Expected: %v requests %v waited, Actual: %v requests %v waited]", 6, 5, stats.Requests, stats.Waited)
+	}
+}
+
+func TestClient_RateLimitFailFast(t *testing.T) {
+	server, attempts := testServer(func(request RPCRequest) RPCResponse {
+		return RPCResponse{Result: "0x1"}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.SetRetryPolicy(fastRetryPolicy())
+	client.SetMethodRateLimit(MethodGetLogs, &RateLimit{RequestsPerSecond: 0.001, Burst: 2, FailFast: true})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Call(MethodGetLogs); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	if _, err := client.Call(MethodGetLogs); err != RateLimited {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", RateLimited, err)
+	}
+
+	if _, err := client.Eth.BlockNumber(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if stats := client.MethodRateLimitStats(MethodGetLogs); stats.Requests != 2 || stats.Rejected != 1 || *attempts != 3 {
+		t.Errorf("wrong stats [Expected: %v passed %v rejected, Actual: %v passed %v rejected]", 2, 1, stats.Requests, stats.Rejected)
+	}
+}
+
+func TestClient_RateLimitRejectedByMethod(t *testing.T) {
+	server, attempts := testServer(func(request RPCRequest) RPCResponse {
+		return RPCResponse{Result: "0x1"}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.SetRetryPolicy(fastRetryPolicy())
+	client.SetRateLimit(&RateLimit{RequestsPerSecond: 0.001, Burst: 2, FailFast: true})
+	client.SetMethodRateLimit(MethodGetLogs, &RateLimit{RequestsPerSecond: 0.001, Burst: 1, FailFast: true})
+
+	if _, err := client.Call(MethodGetLogs); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err := client.Call(MethodGetLogs); err != RateLimited {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", RateLimited, err)
+	}
+
+	// the rejected request must not use up the token of the client limit
+	if _, err := client.Eth.BlockNumber(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if stats := client.RateLimitStats(); stats.Requests != 2 || stats.Rejected != 0 || *attempts != 2 {
+		t.Errorf("wrong client stats [Expected: %v passed %v rejected, Actual: %v passed %v rejected]", 2, 0, stats.Requests, stats.Rejected)
+	}
+
+	if stats := client.MethodRateLimitStats(MethodGetLogs); stats.Requests != 1 || stats.Rejected != 1 {
+		t.Errorf("wrong method stats [Expected: %v passed %v rejected, Actual: %v passed %v rejected]", 1, 1, stats.Requests, stats.Rejected)
+	}
+}
+
+func TestClient_RateLimitMaxInFlight(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+
+		return RPCResponse{Result: "0x1"}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.SetRateLimit(&RateLimit{MaxInFlight: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Eth.BlockNumber(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Errorf("wrong max requests in flight [Expected: %v, Actual: %v]", 2, maxInFlight)
+	}
+}
+
+func TestClient_RateLimitContext(t *testing.T) {
+	client := NewRPCClient(RPCEndpointLocalHost)
+	client.SetRateLimit(&RateLimit{RequestsPerSecond: 0.001, Burst: 1})
+
+	client.limits.acquire(context.Background(), MethodEthBlockNumber)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Eth.BlockNumberContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", context.DeadlineExceeded, err)
+	}
+}
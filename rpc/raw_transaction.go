@@ -0,0 +1,181 @@
+package rpc
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/Leondroids/go-ethereum-rpc/rpcutils"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RawTransaction is a transaction which is signed locally and submitted with eth_sendRawTransaction.
+//
+// Legacy transactions (Type 0) with ChainID set are signed with EIP-155 replay protection (v = chainId * 2 + 35 + recovery id),
+// without, they are signed the pre EIP-155 way (v = 27 + recovery id) and valid on every chain.
+// Access list (Type 1, EIP-2930) and dynamic fee (Type 2, EIP-1559) transactions are serialized as EIP-2718 envelopes
+// and require ChainID. Dynamic fee transactions use MaxFeePerGas and MaxPriorityFeePerGas instead of GasPrice.
+type RawTransaction struct {
+	Type                 byte
+	Nonce                uint64
+	GasPrice             *big.Int
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerGas         *big.Int
+	Gas                  uint64
+	To                   *rpctypes.EtherAddress // nil creates a contract
+	Value                *big.Int
+	Data                 []byte
+	AccessList           rpctypes.AccessList
+	ChainID              *big.Int
+}
+
+// SignedTransaction is the result of RawTransaction.Sign.
+type SignedTransaction struct {
+	Transaction *RawTransaction
+	Raw         rpctypes.HexString // the serialized signed transaction, the parameter of eth_sendRawTransaction
+	Hash        rpctypes.HexString // keccak256 of Raw, the transaction hash
+	From        rpctypes.EtherAddress
+	V           *big.Int // the y parity for typed transactions
+	R           *big.Int
+	S           *big.Int
+}
+
+// fields returns the rlp list of the unsigned transaction.
+func (tx *RawTransaction) fields() ([]interface{}, error) {
+	to := make([]byte, 0)
+	if tx.To != nil {
+		to = tx.To.Bytes()
+	}
+
+	if tx.Type != rpctypes.TransactionTypeLegacy && tx.ChainID == nil {
+		return nil, fmt.Errorf("transaction of type %v requires a chain id", tx.Type)
+	}
+
+	if tx.Type == rpctypes.TransactionTypeLegacy {
+		return []interface{}{tx.Nonce, tx.GasPrice, tx.Gas, to, tx.Value, tx.Data}, nil
+	}
+
+	accessList, err := accessListFields(tx.AccessList)
+	if err != nil {
+		return nil, err
+	}
+
+	switch tx.Type {
+	case rpctypes.TransactionTypeAccessList:
+		return []interface{}{tx.ChainID, tx.Nonce, tx.GasPrice, tx.Gas, to, tx.Value, tx.Data, accessList}, nil
+	case rpctypes.TransactionTypeDynamicFee:
+		return []interface{}{tx.ChainID, tx.Nonce, tx.MaxPriorityFeePerGas, tx.MaxFeePerGas, tx.Gas, to, tx.Value, tx.Data, accessList}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported transaction type %v", tx.Type)
+}
+
+// accessListFields returns the rlp list of the access list, storage keys are left padded to 32 bytes.
+func accessListFields(list rpctypes.AccessList) ([]interface{}, error) {
+	fields := make([]interface{}, len(list))
+
+	for k, tuple := range list {
+		keys := make([]interface{}, len(tuple.StorageKeys))
+		for i, key := range tuple.StorageKeys {
+			if len(key.Bytes()) > 32 {
+				return nil, fmt.Errorf("storage key %v of the access list is longer than 32 bytes", key.String())
+			}
+			keys[i] = key.PadTo(32).Bytes()
+		}
+		fields[k] = []interface{}{tuple.Address.Bytes(), keys}
+	}
+
+	return fields, nil
+}
+
+// encode serializes the given fields: plain rlp for legacy transactions, type || rlp for typed transactions.
+func (tx *RawTransaction) encode(fields []interface{}) ([]byte, error) {
+	encoded, err := rpcutils.EncodeRLP(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.Type == rpctypes.TransactionTypeLegacy {
+		return encoded, nil
+	}
+
+	return append([]byte{tx.Type}, encoded...), nil
+}
+
+// SigningHash returns the hash which is signed: keccak256 of the encoded unsigned transaction,
+// for EIP-155 legacy transactions followed by chainId, 0, 0.
+func (tx *RawTransaction) SigningHash() ([]byte, error) {
+	fields, err := tx.fields()
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.Type == rpctypes.TransactionTypeLegacy && tx.ChainID != nil {
+		fields = append(fields, tx.ChainID, new(big.Int), new(big.Int))
+	}
+
+	encoded, err := tx.encode(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Keccak256(encoded), nil
+}
+
+// Sign signs the transaction with the given secp256k1 private key.
+func (tx *RawTransaction) Sign(key *ecdsa.PrivateKey) (*SignedTransaction, error) {
+	if key == nil {
+		return nil, errors.New("private key cannot be nil")
+	}
+
+	hash, err := tx.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+
+	// [R || S || V] with V the recovery id 0 or 1
+	signature, err := crypto.Sign(hash, key)
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	v := new(big.Int).SetInt64(int64(signature[64]))
+
+	if tx.Type == rpctypes.TransactionTypeLegacy {
+		if tx.ChainID != nil {
+			v = new(big.Int).Mul(tx.ChainID, big.NewInt(2))
+			v.Add(v, big.NewInt(int64(signature[64])+35))
+		} else {
+			v.Add(v, big.NewInt(27))
+		}
+	}
+
+	fields, err := tx.fields()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := tx.encode(append(fields, v, r, s))
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := new(rpctypes.EtherAddress).FromBytes(crypto.PubkeyToAddress(key.PublicKey).Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &SignedTransaction{
+		Transaction: tx,
+		Raw:         *rpctypes.NewHexStringFromBytes(raw),
+		Hash:        *rpctypes.NewHexStringFromBytes(crypto.Keccak256(raw)),
+		From:        *from,
+		V:           v,
+		R:           r,
+		S:           s,
+	}, nil
+}
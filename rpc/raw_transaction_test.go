@@ -0,0 +1,225 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// example of https://github.com/ethereum/EIPs/blob/master/EIPS/eip-155.md
+func eip155Transaction() *RawTransaction {
+	to, _ := new(rpctypes.EtherAddress).FromString("0x3535353535353535353535353535353535353535")
+	value, _ := new(big.Int).SetString("1000000000000000000", 10)
+
+	return &RawTransaction{
+		Nonce:    9,
+		GasPrice: big.NewInt(20000000000),
+		Gas:      21000,
+		To:       to,
+		Value:    value,
+		ChainID:  big.NewInt(1),
+	}
+}
+
+func TestRawTransaction_Sign(t *testing.T) {
+	key, err := crypto.HexToECDSA("4646464646464646464646464646464646464646464646464646464646464646")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tx := eip155Transaction()
+
+	hash, err := tx.SigningHash()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if expected := "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53"; hex.EncodeToString(hash) != expected {
+		t.Errorf("wrong signing hash [Expected: %v, Actual: %v]", expected, hex.EncodeToString(hash))
+	}
+
+	signed, err := tx.Sign(key)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"
+	if signed.Raw.Hash() != expected {
+		t.Errorf("wrong raw transaction [Expected: %v, Actual: %v]", expected, signed.Raw.Hash())
+	}
+
+	if signed.V.Int64() != 37 {
+		t.Errorf("wrong v [Expected: %v, Actual: %v]", 37, signed.V)
+	}
+
+	if expected := "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f"; signed.From.String() != expected {
+		t.Errorf("wrong sender [Expected: %v, Actual: %v]", expected, signed.From.String())
+	}
+}
+
+func TestEth_SendSignedTransaction(t *testing.T) {
+	key, _ := crypto.HexToECDSA("4646464646464646464646464646464646464646464646464646464646464646")
+	signed, err := eip155Transaction().Sign(key)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	var request RPCRequest
+	server, _ := testServer(func(r RPCRequest) RPCResponse {
+		request = r
+		return RPCResponse{Result: signed.Hash.Hash()}
+	})
+	defer server.Close()
+
+	hash, err := NewRPCClient(server.URL).Eth.SendSignedTransaction(signed)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	params, _ := request.Params.([]interface{})
+	if request.Method != MethodSendRawTransaction || len(params) != 1 || params[0] != signed.Raw.Hash() {
+		t.Errorf("wrong request [Expected: %v %v, Actual: %v %v]", MethodSendRawTransaction, signed.Raw.Hash(), request.Method, params)
+	}
+
+	if !hash.IsEqual(&signed.Hash) {
+		t.Errorf("wrong hash [Expected: %v, Actual: %v]", signed.Hash.Hash(), hash.Hash())
+	}
+}
+
+func TestEth_SendTransactionNil(t *testing.T) {
+	eth := NewRPCClient("http://localhost:0").Eth
+
+	if _, err := eth.SendTransaction(nil); err == nil {
+		t.Errorf("nil transaction sent [Expected: %v, Actual: %v]", "error", err)
+	}
+
+	if _, err := eth.SignTransaction(nil); err == nil {
+		t.Errorf("nil transaction signed [Expected: %v, Actual: %v]", "error", err)
+	}
+
+	if _, err := eth.SendSignedTransaction(nil); err == nil {
+		t.Errorf("nil signed transaction sent [Expected: %v, Actual: %v]", "error", err)
+	}
+
+	if _, err := eth.Sign("0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f", nil); err == nil {
+		t.Errorf("nil data signed [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestRawTransaction_AccessListKeys(t *testing.T) {
+	tx := eip155Transaction()
+	tx.Type = rpctypes.TransactionTypeAccessList
+
+	storageKey, _ := rpctypes.NewHexString("0x0000000000000000000000000000000000000000000000000000000000000001")
+	tx.AccessList = rpctypes.AccessList{{Address: *tx.To, StorageKeys: []rpctypes.HexString{*storageKey}}}
+	expected, err := tx.SigningHash()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	shortKey, _ := rpctypes.NewHexString("0x01")
+	tx.AccessList[0].StorageKeys[0] = *shortKey
+	hash, err := tx.SigningHash()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if hex.EncodeToString(hash) != hex.EncodeToString(expected) {
+		t.Errorf("short storage key not padded [Expected: %x, Actual: %x]", expected, hash)
+	}
+
+	tx.AccessList[0].StorageKeys[0] = *rpctypes.NewHexStringFromBytes(make([]byte, 33))
+	if _, err := tx.SigningHash(); err == nil {
+		t.Errorf("storage key longer than 32 bytes accepted [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestRawTransaction_SignDynamicFee(t *testing.T) {
+	key, _ := crypto.HexToECDSA("4646464646464646464646464646464646464646464646464646464646464646")
+	tx := eip155Transaction()
+	tx.Type = rpctypes.TransactionTypeDynamicFee
+	tx.GasPrice = nil
+	tx.MaxPriorityFeePerGas = big.NewInt(2000000000)
+	tx.MaxFeePerGas = big.NewInt(30000000000)
+
+	storageKey, _ := rpctypes.NewHexString("0x0000000000000000000000000000000000000000000000000000000000000001")
+	tx.AccessList = rpctypes.AccessList{{Address: *tx.To, StorageKeys: []rpctypes.HexString{*storageKey}}}
+
+	signed, err := tx.Sign(key)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	raw := signed.Raw.Bytes()
+	if raw[0] != rpctypes.TransactionTypeDynamicFee || raw[1] < 0xf7 {
+		t.Errorf("wrong envelope [Expected: %v followed by a long list, Actual: %x]", rpctypes.TransactionTypeDynamicFee, raw[:2])
+	}
+
+	if signed.V.Int64() > 1 {
+		t.Errorf("wrong y parity [Expected: %v, Actual: %v]", "0 or 1", signed.V)
+	}
+
+	hash, _ := tx.SigningHash()
+	signature := append(append(pad32(signed.R), pad32(signed.S)...), byte(signed.V.Int64()))
+
+	publicKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if recovered := crypto.PubkeyToAddress(*publicKey).Bytes(); hex.EncodeToString(recovered) != hex.EncodeToString(signed.From.Bytes()) {
+		t.Errorf("wrong signer [Expected: %x, Actual: %x]", signed.From.Bytes(), recovered)
+	}
+
+	tx.ChainID = nil
+	if _, err := tx.Sign(key); err == nil {
+		t.Errorf("typed transaction without chain id signed [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func pad32(b *big.Int) []byte {
+	padded := make([]byte, 32)
+	return append(padded, b.Bytes()...)[len(b.Bytes()):]
+}
+
+func TestGetTransactionFromResponse_DynamicFee(t *testing.T) {
+	js := `{"blockHash":"0x1d59ff54b1eb26b013ce3cb5fc9dab3705b415a67127a003c3e61eb445bb8df2","blockNumber":"0xc5043f","from":"0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f",` +
+		`"gas":"0x5208","gasPrice":"0x4a817c800","maxFeePerGas":"0x6fc23ac00","maxPriorityFeePerGas":"0x77359400","hash":"0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b",` +
+		`"input":"0x","nonce":"0x9","to":"0x3535353535353535353535353535353535353535","transactionIndex":"0x1","value":"0xde0b6b3a7640000","type":"0x2",` +
+		`"accessList":[{"address":"0x3535353535353535353535353535353535353535","storageKeys":["0x0000000000000000000000000000000000000000000000000000000000000001"]}],` +
+		`"chainId":"0x1","v":"0x1","yParity":"0x1","r":"0x28ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276","s":"0x67cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"}`
+
+	var result interface{}
+	json.Unmarshal([]byte(js), &result)
+
+	tx, err := getTransactionFromResponse(result)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if tx.Type != rpctypes.TransactionTypeDynamicFee || tx.ChainID != 1 || tx.YParity != 1 {
+		t.Errorf("wrong typed fields [Expected: %v %v %v, Actual: %v %v %v]", 2, 1, 1, tx.Type, tx.ChainID, tx.YParity)
+	}
+
+	if tx.MaxFeePerGas.BigInt().Int64() != 30000000000 || tx.MaxPriorityFeePerGas.BigInt().Int64() != 2000000000 {
+		t.Errorf("wrong fees [Expected: %v %v, Actual: %v %v]", 30000000000, 2000000000, tx.MaxFeePerGas.BigInt(), tx.MaxPriorityFeePerGas.BigInt())
+	}
+
+	if len(tx.AccessList) != 1 || len(tx.AccessList[0].StorageKeys) != 1 {
+		t.Errorf("wrong access list [Expected: %v, Actual: %v]", 1, tx.AccessList)
+	}
+}
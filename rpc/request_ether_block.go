@@ -1,12 +1,18 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"math/big"
 )
 
 func (client *Client) RequestEtherBlock(method string, params ...interface{}) (*rpctypes.EtherBlock, error) {
+	return client.RequestEtherBlockContext(context.Background(), method, params...)
+}
+
+func (client *Client) RequestEtherBlockContext(ctx context.Context, method string, params ...interface{}) (*rpctypes.EtherBlock, error) {
 	p := []interface{}(params)
 
 	if len(p) != 2 {
@@ -15,7 +21,7 @@ func (client *Client) RequestEtherBlock(method string, params ...interface{}) (*
 
 	full := p[1].(bool)
 
-	response, err := checkRPCError(client.Call(method, params...))
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 
 	if err != nil {
 		return nil, err
@@ -58,6 +64,14 @@ func getBlockFromResponse(response interface{}, full bool) (*rpctypes.EtherBlock
 	return r.ToEtherBlock()
 }
 
+func parseBaseFee(baseFeePerGas string) (*big.Int, error) {
+	if baseFeePerGas == "" {
+		return nil, nil
+	}
+
+	return rpctypes.HexToBigInt(baseFeePerGas)
+}
+
 ////////////////////////////////
 //
 // AUX Types
@@ -179,6 +193,12 @@ func (rpcBlock RPCEtherBlockRawWithTransactionHash) ToEtherBlock() (*rpctypes.Et
 		return nil, err
 	}
 
+	baseFeePerGas, err := parseBaseFee(rpcBlock.BaseFeePerGas)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &rpctypes.EtherBlock{
 		Number:           number.Int64(),
 		Hash:             *hash,
@@ -199,6 +219,7 @@ func (rpcBlock RPCEtherBlockRawWithTransactionHash) ToEtherBlock() (*rpctypes.Et
 		Timestamp:        timestamp.Int64(),
 		Transactions:     transactions,
 		Uncles:           uncles,
+		BaseFeePerGas:    baseFeePerGas,
 	}, nil
 }
 
@@ -321,6 +342,12 @@ func (rpcBlock RPCEtherBlockWithFullTransactions) ToEtherBlock() (*rpctypes.Ethe
 		return nil, err
 	}
 
+	baseFeePerGas, err := parseBaseFee(rpcBlock.BaseFeePerGas)
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &rpctypes.EtherBlock{
 		Number:           number.Int64(),
 		Hash:             *hash,
@@ -341,6 +368,7 @@ func (rpcBlock RPCEtherBlockWithFullTransactions) ToEtherBlock() (*rpctypes.Ethe
 		Timestamp:        timestamp.Int64(),
 		TransactionsFull: etl,
 		Uncles:           uncles,
+		BaseFeePerGas:    baseFeePerGas,
 	}, nil
 }
 
@@ -364,6 +392,7 @@ type RPCEtherBlockRawWithTransactionHash struct {
 	Timestamp        string   `json:"timestamp"`        // the unix timestamp for when the block was collated
 	Transactions     []string `json:"transactions"`     // Array of transaction objects, or 32 Bytes transaction hashes depending on the last given parameter
 	Uncles           []string `json:"uncles"`           // Array - Array of uncle hashes
+	BaseFeePerGas    string   `json:"baseFeePerGas"`    // (optional) integer of the EIP-1559 base fee, missing before the london fork
 }
 
 type RPCEtherBlockWithFullTransactions struct {
@@ -386,4 +415,5 @@ type RPCEtherBlockWithFullTransactions struct {
 	Timestamp        string                   `json:"timestamp"`        // the unix timestamp for when the block was collated
 	Transactions     []RPCEtherTransactionRaw `json:"transactions"`     // Array of transaction objects, or 32 Bytes transaction hashes depending on the last given parameter
 	Uncles           []string                 `json:"uncles"`           // Array - Array of uncle hashes
+	BaseFeePerGas    string                   `json:"baseFeePerGas"`    // (optional) integer of the EIP-1559 base fee, missing before the london fork
 }
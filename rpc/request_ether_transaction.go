@@ -1,13 +1,18 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
 )
 
 func (client *Client) RequestEtherTransaction(method string, params ...interface{}) (*rpctypes.EtherTransaction, error) {
-	response, err := checkRPCError(client.Call(method, params...))
+	return client.RequestEtherTransactionContext(context.Background(), method, params...)
+}
+
+func (client *Client) RequestEtherTransactionContext(ctx context.Context, method string, params ...interface{}) (*rpctypes.EtherTransaction, error) {
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 
 	if err != nil {
 		return nil, err
@@ -52,6 +57,13 @@ type RPCEtherTransactionRaw struct {
 	V                string `json:"v"`
 	R                string `json:"r"`
 	S                string `json:"s"`
+	// typed transactions (EIP-2718)
+	Type                 string                    `json:"type"`
+	ChainID              string                    `json:"chainId"`
+	MaxFeePerGas         string                    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas string                    `json:"maxPriorityFeePerGas"`
+	AccessList           []rpctypes.AccessTupleRaw `json:"accessList"`
+	YParity              string                    `json:"yParity"`
 }
 
 func (trans RPCEtherTransactionRaw) toEtherTransaction() (*rpctypes.EtherTransaction, error) {
@@ -139,28 +151,92 @@ func (trans RPCEtherTransactionRaw) toEtherTransaction() (*rpctypes.EtherTransac
 		return nil, err
 	}
 
+	typed, err := trans.toTypedFields()
+
+	if err != nil {
+		return nil, err
+	}
+
 	return &rpctypes.EtherTransaction{
-		Hash:             *hash,
-		BlockHash:        *blockHash,
-		BlockNumber:      blockNumber.Int64(),
-		Gas:              *gas,
-		GasPrice:         *gasPrice,
-		From:             *from,
-		To:               *to,
-		Nonce:            *nonce,
-		Input:            *input,
-		TransactionIndex: transactionIndex.Int64(),
-		Value:            *value,
-		V:                *v,
-		R:                *r,
-		S:                *s,
+		Hash:                 *hash,
+		BlockHash:            *blockHash,
+		BlockNumber:          blockNumber.Int64(),
+		Gas:                  *gas,
+		GasPrice:             *gasPrice,
+		From:                 *from,
+		To:                   *to,
+		Nonce:                *nonce,
+		Input:                *input,
+		TransactionIndex:     transactionIndex.Int64(),
+		Value:                *value,
+		V:                    *v,
+		R:                    *r,
+		S:                    *s,
+		Type:                 typed.Type,
+		ChainID:              typed.ChainID,
+		MaxFeePerGas:         typed.MaxFeePerGas,
+		MaxPriorityFeePerGas: typed.MaxPriorityFeePerGas,
+		AccessList:           typed.AccessList,
+		YParity:              typed.YParity,
 	}, nil
 }
 
+// toTypedFields parses the fields of EIP-2718 transactions, which are missing in legacy transactions.
+func (trans RPCEtherTransactionRaw) toTypedFields() (*rpctypes.EtherTransaction, error) {
+	typed := new(rpctypes.EtherTransaction)
+
+	for _, field := range []struct {
+		value  string
+		target *int64
+	}{
+		{trans.Type, &typed.Type},
+		{trans.ChainID, &typed.ChainID},
+		{trans.YParity, &typed.YParity},
+	} {
+		if field.value == "" {
+			continue
+		}
+		hs, err := rpctypes.NewHexString(field.value)
+		if err != nil {
+			return nil, err
+		}
+		*field.target = hs.Int64()
+	}
+
+	for _, field := range []struct {
+		value  string
+		target *rpctypes.EtherValue
+	}{
+		{trans.MaxFeePerGas, &typed.MaxFeePerGas},
+		{trans.MaxPriorityFeePerGas, &typed.MaxPriorityFeePerGas},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if _, err := field.target.FromHexString(field.value); err != nil {
+			return nil, err
+		}
+	}
+
+	accessList, err := rpctypes.ToAccessList(trans.AccessList)
+
+	if err != nil {
+		return nil, err
+	}
+
+	typed.AccessList = accessList
+
+	return typed, nil
+}
+
 // transaction receipt
 
 func (client *Client) RequestEtherTransactionReceipt(method string, params ...interface{}) (*rpctypes.EtherTransactionReceipt, error) {
-	response, err := checkRPCError(client.Call(method, params...))
+	return client.RequestEtherTransactionReceiptContext(context.Background(), method, params...)
+}
+
+func (client *Client) RequestEtherTransactionReceiptContext(ctx context.Context, method string, params ...interface{}) (*rpctypes.EtherTransactionReceipt, error) {
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 	if err != nil {
 		return nil, err
 	}
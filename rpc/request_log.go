@@ -1,13 +1,18 @@
 package rpc
 
 import (
+	"context"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
 	"fmt"
 	"encoding/json"
 )
 
 func (client *Client) RequestEtherLogList(method string, params ...interface{}) ([]rpctypes.EtherLog, error) {
-	response, err := checkRPCError(client.Call(method, params...))
+	return client.RequestEtherLogListContext(context.Background(), method, params...)
+}
+
+func (client *Client) RequestEtherLogListContext(ctx context.Context, method string, params ...interface{}) ([]rpctypes.EtherLog, error) {
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 
 	if err != nil {
 		return nil, err
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"fmt"
 	"github.com/Leondroids/gox"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
@@ -10,7 +11,11 @@ import (
 	Request EtherValue
  */
 func (client *Client) RequestEtherValue(method string, params ...interface{}) (*rpctypes.EtherValue, error) {
-	response, err := checkRPCError(client.Call(method, params...))
+	return client.RequestEtherValueContext(context.Background(), method, params...)
+}
+
+func (client *Client) RequestEtherValueContext(ctx context.Context, method string, params ...interface{}) (*rpctypes.EtherValue, error) {
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 
 	if err != nil {
 		return nil, err
@@ -35,7 +40,11 @@ func (client *Client) RequestEtherValue(method string, params ...interface{}) (*
 	Request Int64
  */
 func (client *Client) RequestInt64(method string, params ...interface{}) (int64, error) {
-	response, err := checkRPCError(client.Call(method, params...))
+	return client.RequestInt64Context(context.Background(), method, params...)
+}
+
+func (client *Client) RequestInt64Context(ctx context.Context, method string, params ...interface{}) (int64, error) {
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 	if err != nil {
 		return -1, err
 	}
@@ -58,7 +67,11 @@ func (client *Client) RequestInt64(method string, params ...interface{}) (int64,
 }
 
 func (client *Client) RequestString(method string, params ...interface{}) (string, error) {
-	response, err := checkRPCError(client.Call(method, params...))
+	return client.RequestStringContext(context.Background(), method, params...)
+}
+
+func (client *Client) RequestStringContext(ctx context.Context, method string, params ...interface{}) (string, error) {
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 	if err != nil {
 		return "", err
 	}
@@ -76,7 +89,11 @@ func (client *Client) RequestString(method string, params ...interface{}) (strin
 	Request Bool
  */
 func (client *Client) RequestBool(method string, params ...interface{}) (bool, error) {
-	response, err := checkRPCError(client.Call(method, params...))
+	return client.RequestBoolContext(context.Background(), method, params...)
+}
+
+func (client *Client) RequestBoolContext(ctx context.Context, method string, params ...interface{}) (bool, error) {
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 	if err != nil {
 		return false, err
 	}
@@ -98,7 +115,11 @@ func (client *Client) RequestBool(method string, params ...interface{}) (bool, e
 	Request HexString
  */
 func (client *Client) RequestHexString(method string, params ...interface{}) (*rpctypes.HexString, error) {
-	response, err := checkRPCError(client.Call(method, params...))
+	return client.RequestHexStringContext(context.Background(), method, params...)
+}
+
+func (client *Client) RequestHexStringContext(ctx context.Context, method string, params ...interface{}) (*rpctypes.HexString, error) {
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 	if err != nil {
 		return nil, err
 	}
@@ -120,7 +141,11 @@ func (client *Client) RequestHexString(method string, params ...interface{}) (*r
 	Request HexStringList
  */
 func (client *Client) RequestHexStringList(method string, params ...interface{}) ([]rpctypes.HexString, error) {
-	response, err := checkRPCError(client.Call(method, params...))
+	return client.RequestHexStringListContext(context.Background(), method, params...)
+}
+
+func (client *Client) RequestHexStringListContext(ctx context.Context, method string, params ...interface{}) ([]rpctypes.HexString, error) {
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"fmt"
 	"encoding/json"
 	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
@@ -20,7 +21,11 @@ type SyncStatusRaw struct {
 }
 
 func (client *Client) RequestSyncStatus(method string, params ...interface{}) (*SyncStatus, error) {
-	response, err := checkRPCError(client.Call(method, params...))
+	return client.RequestSyncStatusContext(context.Background(), method, params...)
+}
+
+func (client *Client) RequestSyncStatusContext(ctx context.Context, method string, params ...interface{}) (*SyncStatus, error) {
+	response, err := checkRPCError(client.CallContext(ctx, method, params...))
 
 	if err != nil {
 		return nil, err
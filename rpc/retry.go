@@ -0,0 +1,247 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// RPC error codes and messages nodes and providers use for conditions which usually resolve themselves.
+var (
+	RetryableRPCErrorCodes = []int{
+		-32005, // limit exceeded (infura, geth)
+	}
+	RetryableRPCErrorMessages = []string{
+		"header not found",
+		"limit exceeded",
+		"rate limit",
+		"too many requests",
+		"request timed out",
+	}
+)
+
+// RetryPolicy describes how often and how fast failed requests are retried by the Client.
+//
+// The n-th retry waits InitialBackoff * Multiplier^(n-1), capped at MaxBackoff. Jitter (0 to 1) shortens each
+// delay by a random fraction of up to Jitter, so that many clients do not retry in lockstep.
+// An HTTPError with a Retry-After header is never retried earlier than the header asks for.
+type RetryPolicy struct {
+	MaxAttempts    int // attempts in total, including the first one. Values below 2 disable retries.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	// Retryable decides whether a failed attempt is retried. It receives transport errors as well as the
+	// *RPCError of a response. If nil, IsRetryableError is used.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns a policy with 5 attempts and a backoff from 100ms to 5s.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// IsRetryableError reports whether err is likely transient:
+//	transport errors (connection refused / reset, dropped websocket, unparsable response body),
+//	HTTPError with status 408, 429 or 5xx,
+//	RPCError with a code in RetryableRPCErrorCodes or a message containing one of RetryableRPCErrorMessages.
+// Canceled or expired contexts, a closed transport, RateLimited, other http status codes and all other rpc errors are permanent.
+func IsRetryableError(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return false
+	case *RPCError:
+		for _, code := range RetryableRPCErrorCodes {
+			if e.Code == code {
+				return true
+			}
+		}
+		message := strings.ToLower(e.Message)
+		for _, m := range RetryableRPCErrorMessages {
+			if strings.Contains(message, m) {
+				return true
+			}
+		}
+		return false
+	case *HTTPError:
+		return e.StatusCode == 408 || e.StatusCode == 429 || e.StatusCode >= 500
+	case *json.UnsupportedTypeError, *json.UnsupportedValueError, *json.MarshalerError:
+		return false
+	}
+
+	return err != context.Canceled && err != context.DeadlineExceeded && err != TransportClosed && err != RateLimited
+}
+
+// SetRetryPolicy enables retries of Call, CallNamed and Batch (and everything built on top of them) with the given policy.
+// A nil policy disables retries, which is the default.
+func (client *Client) SetRetryPolicy(policy *RetryPolicy) {
+	client.retryPolicy = policy
+}
+
+func (policy *RetryPolicy) retryable(err error) bool {
+	if policy.Retryable != nil {
+		return policy.Retryable(err)
+	}
+	return IsRetryableError(err)
+}
+
+func (policy *RetryPolicy) backoff(attempt int, cause error) time.Duration {
+	delay := float64(policy.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		delay *= policy.Multiplier
+		if delay > float64(policy.MaxBackoff) {
+			break
+		}
+	}
+
+	if policy.MaxBackoff > 0 && delay > float64(policy.MaxBackoff) {
+		delay = float64(policy.MaxBackoff)
+	}
+
+	delay -= delay * policy.Jitter * rand.Float64()
+
+	if httpError, ok := cause.(*HTTPError); ok && float64(httpError.RetryAfter) > delay {
+		delay = float64(httpError.RetryAfter)
+	}
+
+	return time.Duration(delay)
+}
+
+// wait sleeps for the backoff of the given attempt, or until the context is done.
+func (policy *RetryPolicy) wait(ctx context.Context, attempt int, cause error) error {
+	timer := time.NewTimer(policy.backoff(attempt, cause))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryCall sends a single request, retrying transport errors and retryable rpc errors according to the retry policy.
+// If all attempts failed with an rpc error, the last response is returned.
+func (client *Client) retryCall(ctx context.Context, request *RPCRequest) (*RPCResponse, error) {
+	policy := client.retryPolicy
+
+	for attempt := 1; ; attempt++ {
+		release, err := client.limits.acquire(ctx, request.Method)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := client.transport.Call(ctx, request)
+		release()
+
+		cause := err
+		if err == nil && response.Error != nil {
+			cause = response.Error
+		}
+
+		if cause == nil || policy == nil || attempt >= policy.MaxAttempts || !policy.retryable(cause) {
+			return response, err
+		}
+
+		if err := policy.wait(ctx, attempt, cause); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryBatch sends a batch request according to the retry policy. A failed batch is sent again as a whole,
+// of a delivered batch only the requests with retryable rpc errors are sent again.
+func (client *Client) retryBatch(ctx context.Context, requests []interface{}) ([]RPCResponse, error) {
+	policy := client.retryPolicy
+	var result []RPCResponse
+
+	for attempt := 1; ; attempt++ {
+		release, err := client.limits.acquire(ctx, requestMethods(requests)...)
+		if err != nil {
+			return nil, err
+		}
+
+		responses, err := client.transport.Batch(ctx, requests)
+		release()
+		cause := err
+
+		if err == nil {
+			result = mergeResponses(result, responses)
+			requests, cause = client.retryableRequests(requests, responses)
+
+			if len(requests) == 0 {
+				return result, nil
+			}
+		}
+
+		if policy == nil || attempt >= policy.MaxAttempts || !policy.retryable(cause) {
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+
+		if err := policy.wait(ctx, attempt, cause); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryableRequests returns the requests whose response carries a retryable rpc error, and the first of these errors.
+func (client *Client) retryableRequests(requests []interface{}, responses []RPCResponse) ([]interface{}, error) {
+	policy := client.retryPolicy
+	if policy == nil {
+		return nil, nil
+	}
+
+	failed := make(map[uint]*RPCError)
+	for _, r := range responses {
+		if r.Error != nil && policy.retryable(r.Error) {
+			failed[r.ID] = r.Error
+		}
+	}
+
+	retry := make([]interface{}, 0)
+	var cause error
+
+	for _, r := range requests {
+		request, ok := r.(*RPCRequest)
+		if !ok {
+			continue
+		}
+		if rpcError, ok := failed[request.ID]; ok {
+			retry = append(retry, request)
+			if cause == nil {
+				cause = rpcError
+			}
+		}
+	}
+
+	return retry, cause
+}
+
+// mergeResponses replaces responses in result by the responses with the same id, new ids are appended.
+func mergeResponses(result []RPCResponse, responses []RPCResponse) []RPCResponse {
+	index := make(map[uint]int, len(result))
+	for k, r := range result {
+		index[r.ID] = k
+	}
+
+	for _, r := range responses {
+		if k, ok := index[r.ID]; ok {
+			result[k] = r
+		} else {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
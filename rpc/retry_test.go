@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fastRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+// flakyServer answers like testServer, answer gets the number of the attempt (request) starting at 1.
+func flakyServer(answer func(attempt int32, request RPCRequest) RPCResponse) (*httptest.Server, *int32) {
+	attempts := new(int32)
+
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		return answer(atomic.AddInt32(attempts, 1), request)
+	})
+
+	return server, attempts
+}
+
+func TestClient_RetryHTTPStatus(t *testing.T) {
+	server, attempts := flakyServer(func(attempt int32, request RPCRequest) RPCResponse {
+		if attempt < 3 {
+			return RPCResponse{Result: httpStatus(http.StatusServiceUnavailable)}
+		}
+		return RPCResponse{Result: "0x1"}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.SetRetryPolicy(fastRetryPolicy())
+
+	blockNumber, err := client.Eth.BlockNumber()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if blockNumber != 1 || *attempts != 3 {
+		t.Errorf("wrong result [Expected: %v after %v attempts, Actual: %v after %v attempts]", 1, 3, blockNumber, *attempts)
+	}
+}
+
+func TestClient_RetryRPCError(t *testing.T) {
+	server, attempts := flakyServer(func(attempt int32, request RPCRequest) RPCResponse {
+		if attempt < 2 {
+			return RPCResponse{Error: &RPCError{Code: -32005, Message: "limit exceeded"}}
+		}
+		return RPCResponse{Result: "0x1"}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.SetRetryPolicy(fastRetryPolicy())
+
+	if _, err := client.Eth.BlockNumber(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	if *attempts != 2 {
+		t.Errorf("wrong number of attempts [Expected: %v, Actual: %v]", 2, *attempts)
+	}
+}
+
+func TestClient_RetryPermanentError(t *testing.T) {
+	server, attempts := flakyServer(func(attempt int32, request RPCRequest) RPCResponse {
+		return RPCResponse{Error: &RPCError{Code: -32601, Message: "Method not found"}}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.SetRetryPolicy(fastRetryPolicy())
+
+	_, err := client.Eth.BlockNumber()
+	if rpcError, ok := err.(*RPCError); !ok || rpcError.Code != -32601 {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", -32601, err)
+	}
+
+	if *attempts != 1 {
+		t.Errorf("permanent errors should not be retried [Expected: %v, Actual: %v]", 1, *attempts)
+	}
+}
+
+func TestClient_RetryExhausted(t *testing.T) {
+	server, attempts := flakyServer(func(attempt int32, request RPCRequest) RPCResponse {
+		return RPCResponse{Result: httpStatus(http.StatusTooManyRequests)}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	policy := fastRetryPolicy()
+	policy.MaxAttempts = 3
+	client.SetRetryPolicy(policy)
+
+	_, err := client.Eth.BlockNumber()
+	if httpError, ok := err.(*HTTPError); !ok || httpError.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", http.StatusTooManyRequests, err)
+	}
+
+	if *attempts != 3 {
+		t.Errorf("wrong number of attempts [Expected: %v, Actual: %v]", 3, *attempts)
+	}
+}
+
+func TestClient_RetryBatch(t *testing.T) {
+	requests, blockRequests := new(int32), new(int32)
+	server, batches := testServer(func(request RPCRequest) RPCResponse {
+		atomic.AddInt32(requests, 1)
+		if request.Method == MethodGetBlockByNumber && atomic.AddInt32(blockRequests, 1) == 1 {
+			return RPCResponse{Error: &RPCError{Code: -32000, Message: "header not found"}}
+		}
+		return RPCResponse{Result: "0x1"}
+	})
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	client.SetRetryPolicy(fastRetryPolicy())
+
+	r1 := client.NewRPCRequestObject(MethodEthBlockNumber)
+	r2 := client.NewRPCRequestObject(MethodGetBlockByNumber, "0x1", false)
+
+	batch, err := client.Batch(r1, r2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, r := range []*RPCRequest{r1, r2} {
+		if _, err := checkRPCError(batch.GetResponseOf(r)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	// only the failed request is sent again
+	if *batches != 2 || *requests != 3 {
+		t.Errorf("wrong requests [Expected: %v in %v batches, Actual: %v in %v batches]", 3, 2, *requests, *batches)
+	}
+}
@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+var (
+	// selector of Error(string), used by require() and revert() with a message
+	revertErrorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// selector of Panic(uint256), used by failing asserts, overflows, division by zero etc. since solidity 0.8
+	revertPanicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// RevertError is returned instead of an RPCError if the node reports that the execution reverted.
+type RevertError struct {
+	RPCError *RPCError
+	Reason   string             // the decoded revert reason, empty if the contract reverted without one
+	Data     rpctypes.HexString // the raw revert data
+}
+
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + e.Reason
+}
+
+// asRevertError turns an RPCError which carries revert data (geth: data "0x08c379a0...", parity: data "Reverted 0x...")
+// or reports "execution reverted" into a RevertError. All other errors are returned unchanged.
+func asRevertError(err error) error {
+	rpcError, ok := err.(*RPCError)
+	if !ok {
+		return err
+	}
+
+	data, hasData := revertData(rpcError.Data)
+	reverted := strings.Contains(strings.ToLower(rpcError.Message), "revert")
+
+	if !hasData && !reverted {
+		return err
+	}
+
+	revertError := &RevertError{RPCError: rpcError}
+
+	if hasData {
+		revertError.Data = *data
+		revertError.Reason = DecodeRevertReason(data.Bytes())
+	}
+
+	if revertError.Reason == "" {
+		if k := strings.Index(rpcError.Message, "execution reverted: "); k >= 0 {
+			revertError.Reason = rpcError.Message[k+len("execution reverted: "):]
+		}
+	}
+
+	return revertError
+}
+
+func revertData(data interface{}) (*rpctypes.HexString, bool) {
+	var s string
+
+	switch d := data.(type) {
+	case string:
+		s = d
+	case map[string]interface{}:
+		s, _ = d["data"].(string)
+	}
+
+	s = strings.TrimSpace(strings.TrimPrefix(s, "Reverted"))
+	if !strings.HasPrefix(s, "0x") || len(s) < 10 {
+		return nil, false
+	}
+
+	hs, err := rpctypes.NewHexString(s)
+	if err != nil {
+		return nil, false
+	}
+
+	return hs, true
+}
+
+// DecodeRevertReason decodes the message of Error(string) and the code of Panic(uint256) revert data.
+// It returns an empty string for custom errors and malformed data.
+func DecodeRevertReason(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+
+	selector, body := data[:4], data[4:]
+
+	switch {
+	case string(selector) == string(revertErrorSelector):
+		if len(body) < 64 {
+			return ""
+		}
+		// compare against the remaining bytes, offset+32 and start+32+length could overflow
+		offset := new(big.Int).SetBytes(body[:32])
+		if !offset.IsUint64() || offset.Uint64() > uint64(len(body)-32) {
+			return ""
+		}
+		start := int(offset.Uint64())
+		length := new(big.Int).SetBytes(body[start : start+32])
+		if !length.IsUint64() || length.Uint64() > uint64(len(body)-start-32) {
+			return ""
+		}
+		return string(body[start+32 : start+32+int(length.Uint64())])
+	case string(selector) == string(revertPanicSelector):
+		if len(body) < 32 {
+			return ""
+		}
+		return fmt.Sprintf("panic 0x%x", binary.BigEndian.Uint64(body[24:32]))
+	}
+
+	return ""
+}
@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+// "0x08c379a0" ++ abi.encode("insufficient balance")
+const insufficientBalanceRevert = "0x08c379a0" +
+	"0000000000000000000000000000000000000000000000000000000000000020" +
+	"0000000000000000000000000000000000000000000000000000000000000014" +
+	"696e73756666696369656e742062616c616e6365000000000000000000000000"
+
+func TestDecodeRevertReason(t *testing.T) {
+	data, _ := hex.DecodeString(insufficientBalanceRevert[2:])
+
+	if reason := DecodeRevertReason(data); reason != "insufficient balance" {
+		t.Errorf("wrong reason [Expected: %v, Actual: %v]", "insufficient balance", reason)
+	}
+
+	panicData, _ := hex.DecodeString("4e487b71" + "0000000000000000000000000000000000000000000000000000000000000011")
+
+	if reason := DecodeRevertReason(panicData); reason != "panic 0x11" {
+		t.Errorf("wrong reason [Expected: %v, Actual: %v]", "panic 0x11", reason)
+	}
+
+	if reason := DecodeRevertReason(data[:40]); reason != "" {
+		t.Errorf("malformed data decoded [Expected: %v, Actual: %v]", "", reason)
+	}
+
+	hugeOffset, _ := hex.DecodeString("08c379a0" +
+		"000000000000000000000000000000000000000000000000ffffffffffffffff" +
+		"0000000000000000000000000000000000000000000000000000000000000014")
+	hugeLength, _ := hex.DecodeString("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000ffffffffffffffe1" +
+		"696e73756666696369656e742062616c616e6365000000000000000000000000")
+
+	for _, malformed := range [][]byte{hugeOffset, hugeLength} {
+		if reason := DecodeRevertReason(malformed); reason != "" {
+			t.Errorf("malformed data decoded [Expected: %v, Actual: %v]", "", reason)
+		}
+	}
+}
+
+func TestEth_EstimateGasRequest(t *testing.T) {
+	var request RPCRequest
+	server, _ := testServer(func(r RPCRequest) RPCResponse {
+		request = r
+		return RPCResponse{Result: "0x5208"}
+	})
+	defer server.Close()
+
+	value, _ := new(big.Int).SetString("1000000000000000000000", 10)
+
+	gas, err := NewRPCClient(server.URL).Eth.EstimateGas(&EthEstimateGasParams{
+		From:  "0x407d73d8a49eeb85d32cf465507dd71d507100c1",
+		To:    "0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b",
+		Value: value,
+	})
+
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if gas.Int64() != 21000 {
+		t.Errorf("wrong gas [Expected: %v, Actual: %v]", 21000, gas)
+	}
+
+	params, _ := json.Marshal(request.Params)
+	expected := `[{"from":"0x407d73d8a49eeb85d32cf465507dd71d507100c1","to":"0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b","value":"0x3635c9adc5dea00000"}]`
+
+	if request.Method != "eth_estimateGas" || string(params) != expected {
+		t.Errorf("wrong request [Expected: %v %v, Actual: %v %v]", "eth_estimateGas", expected, request.Method, string(params))
+	}
+}
+
+func TestEth_EstimateGasRevert(t *testing.T) {
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		return RPCResponse{Error: &RPCError{
+			Code:    3,
+			Message: "execution reverted: insufficient balance",
+			Data:    insufficientBalanceRevert,
+		}}
+	})
+	defer server.Close()
+
+	_, err := NewRPCClient(server.URL).Eth.EstimateGas(&EthEstimateGasParams{To: "0xa94f5374fce5edbc8e2a8697c15331677e6ebf0b"})
+
+	revertError, ok := err.(*RevertError)
+	if !ok {
+		t.Errorf("wrong error type [Expected: %v, Actual: %T]", "*RevertError", err)
+		return
+	}
+
+	if revertError.Reason != "insufficient balance" || revertError.RPCError.Code != 3 {
+		t.Errorf("wrong revert error [Expected: %v, Actual: %v]", "insufficient balance", revertError.Reason)
+	}
+}
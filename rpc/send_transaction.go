@@ -12,17 +12,35 @@ type SendTransaction struct {
 	GasPrice *big.Int               `json:"gasPrice"`
 	Value    *big.Int               `json:"value"`
 	Data     *rpctypes.HexString    `json:"data"`
+	Nonce    *big.Int               `json:"nonce"`
 }
 
+// ToMap returns the transaction object of the request. Unset fields are omitted, the node fills in defaults
+// (e.g. the nonce and gas price) and a missing to address creates a contract.
 func (it *SendTransaction) ToMap() map[string]interface{} {
 	m := make(map[string]interface{})
 
-	m["from"] = it.From.Hash()
-	m["to"] = it.To.Hash()
-	m["gas"] = new(rpctypes.HexString).FromBytes(it.Gas.Bytes())
-	m["gasPrice"] = new(rpctypes.HexString).FromBytes(it.GasPrice.Bytes())
-	m["value"] = new(rpctypes.HexString).FromBytes(it.Value.Bytes())
-	m["data"] = it.Data.Hash()
+	if it.From != nil {
+		m["from"] = it.From.Hash()
+	}
+	if it.To != nil {
+		m["to"] = it.To.Hash()
+	}
+	if it.Gas != nil {
+		m["gas"] = rpctypes.BigIntToHex(it.Gas)
+	}
+	if it.GasPrice != nil {
+		m["gasPrice"] = rpctypes.BigIntToHex(it.GasPrice)
+	}
+	if it.Value != nil {
+		m["value"] = rpctypes.BigIntToHex(it.Value)
+	}
+	if it.Data != nil {
+		m["data"] = it.Data.Hash()
+	}
+	if it.Nonce != nil {
+		m["nonce"] = rpctypes.BigIntToHex(it.Nonce)
+	}
 
 	return m
 }
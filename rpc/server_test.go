@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+)
+
+// httpStatus as Result of a response makes testServer fail the whole HTTP request with the status code.
+type httpStatus int
+
+// testServer is a mock node which answers every JSON-RPC request with answer, single requests as well as batches.
+// The jsonrpc version and id of the responses are set by the server. Batch responses are returned in reverse order,
+// clients must match them by id. It counts the HTTP requests, a batch counts once.
+func testServer(answer func(request RPCRequest) RPCResponse) (*httptest.Server, *int32) {
+	calls := new(int32)
+
+	respond := func(request RPCRequest) RPCResponse {
+		response := answer(request)
+		response.JSONRPC, response.ID = "2.0", request.ID
+		return response
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		body, _ := ioutil.ReadAll(r.Body)
+
+		if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+			request := RPCRequest{}
+			json.Unmarshal(body, &request)
+			response := respond(request)
+			if status, ok := response.Result.(httpStatus); ok {
+				w.WriteHeader(int(status))
+				return
+			}
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		requests := make([]RPCRequest, 0)
+		json.Unmarshal(body, &requests)
+
+		responses := make([]RPCResponse, len(requests))
+		for k, request := range requests {
+			response := respond(request)
+			if status, ok := response.Result.(httpStatus); ok {
+				w.WriteHeader(int(status))
+				return
+			}
+			responses[len(requests)-1-k] = response
+		}
+		json.NewEncoder(w).Encode(responses)
+	})), calls
+}
@@ -0,0 +1,346 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	resubscribeMinBackoff = 100 * time.Millisecond
+	resubscribeMaxBackoff = 30 * time.Second
+	unsubscribeTimeout    = 10 * time.Second
+)
+
+// subscriber is implemented by transports which can receive server side notifications.
+type subscriber interface {
+	subscribe(ctx context.Context, sub *Subscription) error
+	unsubscribe(sub *Subscription)
+}
+
+// Subscription is an active eth_subscribe subscription. Notifications are decoded and delivered
+// on the channel that was passed when subscribing, in the order the node sent them.
+//
+// If the connection drops, the subscription is renewed automatically once the transport reconnected.
+// Notifications the node emitted while the connection was down are lost.
+type Subscription struct {
+	transport subscriber
+	params    []interface{}
+	decode    func(result json.RawMessage) (interface{}, error)
+	send      func(value interface{}, quit <-chan struct{}) bool
+
+	mu     sync.Mutex
+	id     string
+	queue  []interface{}
+	wake   chan struct{}
+	quit   chan struct{}
+	err    chan error
+	failed bool
+	done   bool
+}
+
+func newSubscription(params []interface{}, decode func(json.RawMessage) (interface{}, error), send func(interface{}, <-chan struct{}) bool) *Subscription {
+	return &Subscription{
+		params: params,
+		decode: decode,
+		send:   send,
+		wake:   make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+		err:    make(chan error, 1),
+	}
+}
+
+// ID returns the id the node assigned to the subscription. It changes when the subscription is renewed after a reconnect.
+func (sub *Subscription) ID() string {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.id
+}
+
+// Err returns the subscription error channel. At most one error is sent on it, after which
+// no further notifications are delivered. The channel is closed by Unsubscribe().
+func (sub *Subscription) Err() <-chan error {
+	return sub.err
+}
+
+// Unsubscribe stops the delivery of notifications, sends eth_unsubscribe to the node and closes the error channel.
+// It is safe to call Unsubscribe more than once.
+func (sub *Subscription) Unsubscribe() {
+	sub.mu.Lock()
+	if sub.done {
+		sub.mu.Unlock()
+		return
+	}
+	sub.done = true
+	if !sub.failed {
+		close(sub.quit)
+	}
+	close(sub.err)
+	sub.mu.Unlock()
+
+	sub.transport.unsubscribe(sub)
+}
+
+// fail reports err on the error channel and stops the delivery of notifications.
+func (sub *Subscription) fail(err error) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.done || sub.failed {
+		return
+	}
+	sub.failed = true
+	sub.err <- err
+	close(sub.quit)
+}
+
+// active reports whether the subscription still wants notifications.
+func (sub *Subscription) active() bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return !sub.done && !sub.failed
+}
+
+func (sub *Subscription) setID(id string) {
+	sub.mu.Lock()
+	sub.id = id
+	sub.mu.Unlock()
+}
+
+// deliver queues a raw notification result. It never blocks, so a slow consumer does not stall the connection.
+func (sub *Subscription) deliver(result json.RawMessage) {
+	value, err := sub.decode(result)
+	if err != nil {
+		sub.fail(fmt.Errorf("could not decode notification of subscription %v: %v", sub.ID(), err))
+		return
+	}
+
+	sub.mu.Lock()
+	sub.queue = append(sub.queue, value)
+	sub.mu.Unlock()
+
+	select {
+	case sub.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (sub *Subscription) forward() {
+	for {
+		sub.mu.Lock()
+		if len(sub.queue) == 0 {
+			sub.mu.Unlock()
+			select {
+			case <-sub.wake:
+				continue
+			case <-sub.quit:
+				return
+			}
+		}
+		value := sub.queue[0]
+		sub.queue = sub.queue[1:]
+		sub.mu.Unlock()
+
+		if !sub.send(value, sub.quit) {
+			return
+		}
+	}
+}
+
+func (client *Client) subscribe(ctx context.Context, sub *Subscription) error {
+	transport, ok := client.transport.(subscriber)
+	if !ok {
+		return SubscriptionsNotSupported
+	}
+
+	sub.transport = transport
+
+	if err := transport.subscribe(ctx, sub); err != nil {
+		return err
+	}
+
+	go sub.forward()
+
+	return nil
+}
+
+// subscribe sends eth_subscribe and registers sub under the returned id. The registration happens on the
+// reader goroutine, so notifications that directly follow the response are not lost.
+func (t *streamTransport) subscribe(ctx context.Context, sub *Subscription) error {
+	request := &RPCRequest{
+		JSONRPC: "2.0",
+		Method:  MethodSubscribe,
+		Params:  sub.params,
+	}
+
+	response, err := t.call(ctx, request, func(response *RPCResponse) {
+		id, ok := response.Result.(string)
+		if response.Error != nil || !ok || !sub.active() {
+			return
+		}
+
+		sub.setID(id)
+		t.mu.Lock()
+		t.subscriptions[id] = sub
+		t.mu.Unlock()
+	})
+
+	if err == nil && response.Error != nil {
+		err = response.Error
+	}
+
+	if err == nil {
+		if _, ok := response.Result.(string); !ok {
+			err = fmt.Errorf("could not parse subscription id from %v", response.Result)
+		}
+	}
+
+	if err != nil {
+		t.removeSubscription(sub)
+		return err
+	}
+
+	return nil
+}
+
+func (t *streamTransport) unsubscribe(sub *Subscription) {
+	if !t.removeSubscription(sub) {
+		return
+	}
+
+	t.mu.Lock()
+	connected := t.conn != nil
+	t.mu.Unlock()
+
+	if !connected {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
+	defer cancel()
+
+	t.call(ctx, &RPCRequest{
+		JSONRPC: "2.0",
+		Method:  MethodUnsubscribe,
+		Params:  []interface{}{sub.ID()},
+	}, nil)
+}
+
+func (t *streamTransport) removeSubscription(sub *Subscription) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id, s := range t.subscriptions {
+		if s == sub {
+			delete(t.subscriptions, id)
+			return true
+		}
+	}
+
+	return false
+}
+
+// dispatch routes the params of an eth_subscription notification to its subscription.
+func (t *streamTransport) dispatch(params json.RawMessage) {
+	notification := struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	}{}
+
+	if err := json.Unmarshal(params, &notification); err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	sub, ok := t.subscriptions[notification.Subscription]
+	t.mu.Unlock()
+
+	if ok {
+		sub.deliver(notification.Result)
+	}
+}
+
+func (t *streamTransport) startResubscribe() {
+	t.mu.Lock()
+	start := !t.closed && !t.resubscribing && len(t.subscriptions) > 0
+	if start {
+		t.resubscribing = true
+	}
+	t.mu.Unlock()
+
+	if start {
+		go t.resubscribe()
+	}
+}
+
+// resubscribe reconnects with exponential backoff and renews all subscriptions on the new connection.
+func (t *streamTransport) resubscribe() {
+	backoff := resubscribeMinBackoff
+
+	for {
+		t.mu.Lock()
+		closed := t.closed
+		subscriptions := make([]*Subscription, 0, len(t.subscriptions))
+		for _, sub := range t.subscriptions {
+			subscriptions = append(subscriptions, sub)
+		}
+		t.subscriptions = make(map[string]*Subscription)
+		t.mu.Unlock()
+
+		if closed {
+			for _, sub := range subscriptions {
+				sub.fail(TransportClosed)
+			}
+			break
+		}
+
+		retry := make([]*Subscription, 0)
+		for _, sub := range subscriptions {
+			if !sub.active() {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), resubscribeMaxBackoff)
+			err := t.subscribe(ctx, sub)
+			cancel()
+
+			if _, ok := err.(*RPCError); ok {
+				sub.fail(err)
+			} else if err != nil {
+				retry = append(retry, sub)
+			}
+		}
+
+		if len(retry) == 0 {
+			break
+		}
+
+		// keep waiting subscriptions visible to Close() and Unsubscribe()
+		t.mu.Lock()
+		for _, sub := range retry {
+			t.subscriptions[sub.ID()] = sub
+		}
+		t.mu.Unlock()
+
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > resubscribeMaxBackoff {
+			backoff = resubscribeMaxBackoff
+		}
+	}
+
+	t.mu.Lock()
+	t.resubscribing = false
+	t.mu.Unlock()
+
+	// the connection might have dropped again while subscriptions were renewed
+	t.mu.Lock()
+	dropped := t.conn == nil
+	t.mu.Unlock()
+
+	if dropped {
+		t.startResubscribe()
+	}
+}
@@ -0,0 +1,175 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/gorilla/websocket"
+)
+
+const testHeader = `{"number":"%#x","hash":"0x%064x","parentHash":"0x%064x","nonce":"0x0000000000000000",` +
+	`"sha3Uncles":"0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347","logsBloom":"0x00",` +
+	`"transactionsRoot":"0x00","stateRoot":"0x00","receiptsRoot":"0x00","miner":"0x0000000000000000000000000000000000000000",` +
+	`"difficulty":"0x1","extraData":"0x","gasLimit":"0x1","gasUsed":"0x0","timestamp":"0x5b4f5c2c"}`
+
+// subscriptionTestServer confirms eth_subscribe with the id 0x<connection number> and pushes
+// `headers` newHeads notifications right after the response. The first connection is dropped afterwards if dropFirst is set.
+func subscriptionTestServer(t *testing.T, headers int, dropFirst bool) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	var mu sync.Mutex
+	connections := 0
+	block := 0
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		mu.Lock()
+		connections++
+		connection := connections
+		mu.Unlock()
+
+		for {
+			request := RPCRequest{}
+			if err := conn.ReadJSON(&request); err != nil {
+				return
+			}
+
+			switch request.Method {
+			case MethodSubscribe:
+				id := fmt.Sprintf("0x%x", connection)
+				conn.WriteJSON(RPCResponse{JSONRPC: "2.0", ID: request.ID, Result: id})
+
+				for i := 0; i < headers; i++ {
+					mu.Lock()
+					block++
+					header := fmt.Sprintf(testHeader, block, block, block-1)
+					mu.Unlock()
+					conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(
+						`{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":"%v","result":%v}}`, id, header)))
+				}
+
+				if dropFirst && connection == 1 {
+					return
+				}
+			case MethodUnsubscribe:
+				conn.WriteJSON(RPCResponse{JSONRPC: "2.0", ID: request.ID, Result: true})
+			}
+		}
+	}))
+}
+
+func TestEth_SubscribeNewHeads(t *testing.T) {
+	server := subscriptionTestServer(t, 3, false)
+	defer server.Close()
+
+	client := NewRPCClient(websocketEndpoint(server))
+	defer client.Close()
+
+	headers := make(chan rpctypes.EtherBlock)
+	sub, err := client.Eth.SubscribeNewHeads(headers)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	for i := int64(1); i <= 3; i++ {
+		select {
+		case header := <-headers:
+			if header.Number != i {
+				t.Errorf("wrong block number [Expected: %v, Actual: %v]", i, header.Number)
+			}
+		case err := <-sub.Err():
+			t.Error(err)
+			return
+		case <-time.After(5 * time.Second):
+			t.Errorf("timeout waiting for header %v", i)
+			return
+		}
+	}
+}
+
+func TestEth_SubscribeNewHeadsResubscribe(t *testing.T) {
+	server := subscriptionTestServer(t, 1, true)
+	defer server.Close()
+
+	client := NewRPCClient(websocketEndpoint(server))
+	defer client.Close()
+
+	headers := make(chan rpctypes.EtherBlock)
+	sub, err := client.Eth.SubscribeNewHeads(headers)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for i := int64(1); i <= 2; i++ {
+		select {
+		case header := <-headers:
+			if header.Number != i {
+				t.Errorf("wrong block number [Expected: %v, Actual: %v]", i, header.Number)
+			}
+		case err := <-sub.Err():
+			t.Error(err)
+			return
+		case <-time.After(5 * time.Second):
+			t.Errorf("timeout waiting for header %v", i)
+			return
+		}
+	}
+
+	if sub.ID() != "0x2" {
+		t.Errorf("subscription was not renewed [Expected: %v, Actual: %v]", "0x2", sub.ID())
+	}
+
+	sub.Unsubscribe()
+
+	if _, ok := <-sub.Err(); ok {
+		t.Errorf("error channel should be closed after unsubscribe")
+	}
+}
+
+func TestEth_SubscribeOverHTTP(t *testing.T) {
+	_, err := NewRPCClient(RPCEndpointLocalHost).Eth.SubscribeNewPendingTransactions(make(chan rpctypes.HexString))
+
+	if err != SubscriptionsNotSupported {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", SubscriptionsNotSupported, err)
+	}
+}
+
+func TestDecodeSyncingNotification(t *testing.T) {
+	for _, js := range []string{
+		`false`,
+		`{"syncing":false}`,
+	} {
+		status, err := decodeSyncingNotification(json.RawMessage(js))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if status.(*SyncStatus).IsSyncing {
+			t.Errorf("%v should not be syncing", js)
+		}
+	}
+
+	status, err := decodeSyncingNotification(json.RawMessage(`{"syncing":true,"status":{"startingBlock":"0x1","currentBlock":"0x5","highestBlock":"0xa"}}`))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if s := status.(*SyncStatus); !s.IsSyncing || s.CurrentBlock != 5 || s.HighestBlock != 10 {
+		t.Errorf("wrong sync status %+v", s)
+	}
+}
@@ -0,0 +1,18 @@
+package rpc
+
+import "context"
+
+// Transport delivers jsonrpc messages to an rpc backend and returns its responses.
+//
+// Implementations must be safe for concurrent use. The ID of a returned RPCResponse always
+// matches the ID of the RPCRequest it answers, regardless of how the transport frames its messages.
+type Transport interface {
+	// Call sends a single request and waits for its response.
+	Call(ctx context.Context, request *RPCRequest) (*RPCResponse, error)
+	// Notify sends a notification, no response is expected.
+	Notify(ctx context.Context, notification *RPCNotification) error
+	// Batch sends a list of *RPCRequest and *RPCNotification values as one batch.
+	Batch(ctx context.Context, requests []interface{}) ([]RPCResponse, error)
+	// Close releases all resources held by the transport.
+	Close() error
+}
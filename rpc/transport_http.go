@@ -0,0 +1,158 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPError is returned if the backend answered with a non 2xx status code and the body
+// did not contain a jsonrpc error object, e.g. the 429 of a rate limiting proxy.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Body       string
+	RetryAfter time.Duration // parsed from the Retry-After header, 0 if not present
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return e.Status
+	}
+	return fmt.Sprintf("%v: %v", e.Status, e.Body)
+}
+
+// httpTransport sends every jsonrpc message as a separate http POST request.
+// Endpoint, http.Client and custom headers are read from the owning Client on each request,
+// so changes through SetHTTPClient() or SetCustomHeader() take effect immediately.
+type httpTransport struct {
+	client *Client
+}
+
+func newHTTPTransport(client *Client) *httpTransport {
+	return &httpTransport{client: client}
+}
+
+func (t *httpTransport) Call(ctx context.Context, request *RPCRequest) (*RPCResponse, error) {
+	httpResponse, err := t.post(ctx, request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer httpResponse.Body.Close()
+	decoder := json.NewDecoder(httpResponse.Body)
+	decoder.UseNumber()
+	rpcResponse := RPCResponse{}
+	err = decoder.Decode(&rpcResponse)
+
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	return &rpcResponse, nil
+}
+
+func (t *httpTransport) Notify(ctx context.Context, notification *RPCNotification) error {
+	httpResponse, err := t.post(ctx, notification)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+	return nil
+}
+
+func (t *httpTransport) Batch(ctx context.Context, requests []interface{}) ([]RPCResponse, error) {
+	httpResponse, err := t.post(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	rpcResponses := []RPCResponse{}
+	decoder := json.NewDecoder(httpResponse.Body)
+	decoder.UseNumber()
+	err = decoder.Decode(&rpcResponses)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	return rpcResponses, nil
+}
+
+func (t *httpTransport) Close() error {
+	return nil
+}
+
+func (t *httpTransport) post(ctx context.Context, message interface{}) (*http.Response, error) {
+	body, err := json.Marshal(message)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequest("POST", t.client.endpoint, bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	request = request.WithContext(ctx)
+
+	for k, v := range t.client.customHeaders {
+		request.Header.Add(k, v)
+	}
+
+	for k, v := range requestHeaders(ctx) {
+		request.Header[k] = v
+	}
+
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Accept", "application/json")
+
+	httpResponse, err := t.client.httpClient.Do(request)
+
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	if httpResponse.StatusCode < 200 || httpResponse.StatusCode > 299 {
+		return checkHTTPStatus(ctx, httpResponse)
+	}
+
+	return httpResponse, nil
+}
+
+// checkHTTPStatus turns a failed http response into an HTTPError. If the body is a jsonrpc error object
+// the response is passed on instead, since the rpc error carries more information than the status code.
+func checkHTTPStatus(ctx context.Context, httpResponse *http.Response) (*http.Response, error) {
+	body, err := ioutil.ReadAll(httpResponse.Body)
+	httpResponse.Body.Close()
+
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	rpcResponse := RPCResponse{}
+	if err := json.Unmarshal(body, &rpcResponse); err == nil && rpcResponse.Error != nil {
+		httpResponse.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return httpResponse, nil
+	}
+
+	httpError := &HTTPError{
+		StatusCode: httpResponse.StatusCode,
+		Status:     httpResponse.Status,
+		Body:       string(body),
+	}
+
+	if seconds, err := strconv.Atoi(httpResponse.Header.Get("Retry-After")); err == nil {
+		httpError.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return nil, httpError
+}
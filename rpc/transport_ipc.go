@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+)
+
+const ipcWriteTimeout = 10 * time.Second
+
+// ipcConn is a streamConn over a unix domain socket, e.g. the jsonrpc.ipc / geth.ipc file of a local node.
+// Outgoing messages are terminated by a newline, incoming messages are split by json object boundaries,
+// so it works with nodes that do and that do not delimit their responses by newlines.
+type ipcConn struct {
+	conn    net.Conn
+	decoder *json.Decoder
+}
+
+func newIPCTransport(path string) *streamTransport {
+	return newStreamTransport(func(ctx context.Context) (streamConn, error) {
+		return dialIPC(ctx, path)
+	})
+}
+
+// isIPCEndpoint reports whether the endpoint is a path to a unix domain socket rather than an url.
+func isIPCEndpoint(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "/") || strings.HasSuffix(endpoint, ".ipc")
+}
+
+func dialIPC(ctx context.Context, path string) (*ipcConn, error) {
+	dialer := net.Dialer{}
+
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ipcConn{
+		conn:    conn,
+		decoder: json.NewDecoder(conn),
+	}, nil
+}
+
+func (ic *ipcConn) WriteMessage(ctx context.Context, message []byte) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(ipcWriteTimeout)
+	}
+
+	ic.conn.SetWriteDeadline(deadline)
+
+	_, err := ic.conn.Write(append(message, '\n'))
+	return err
+}
+
+func (ic *ipcConn) ReadMessage() ([]byte, error) {
+	message := json.RawMessage{}
+
+	if err := ic.decoder.Decode(&message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func (ic *ipcConn) Close() error {
+	return ic.conn.Close()
+}
@@ -0,0 +1,137 @@
+package rpc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// ipcTestServer answers eth_blockNumber with 0x4b7 and eth_getBalance with an rpc error.
+// Responses are written without delimiter and split across two writes.
+func ipcTestServer(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "rpc-ipc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "jsonrpc.ipc")
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+				decoder := json.NewDecoder(conn)
+
+				for {
+					message := json.RawMessage{}
+					if err := decoder.Decode(&message); err != nil {
+						return
+					}
+
+					requests := make([]RPCRequest, 0)
+					batch := message[0] == '['
+					if batch {
+						json.Unmarshal(message, &requests)
+					} else {
+						request := RPCRequest{}
+						json.Unmarshal(message, &request)
+						requests = append(requests, request)
+					}
+
+					responses := make([]RPCResponse, len(requests))
+					for k, r := range requests {
+						responses[k] = RPCResponse{JSONRPC: "2.0", ID: r.ID}
+						switch r.Method {
+						case MethodEthBlockNumber:
+							responses[k].Result = "0x4b7"
+						default:
+							responses[k].Error = &RPCError{Code: -32601, Message: "Method not found"}
+						}
+					}
+
+					var out []byte
+					if batch {
+						out, _ = json.Marshal(responses)
+					} else {
+						out, _ = json.Marshal(responses[0])
+					}
+
+					conn.Write(out[:len(out)/2])
+					conn.Write(out[len(out)/2:])
+				}
+			}(conn)
+		}
+	}()
+
+	return path, func() {
+		listener.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestIPCTransport_Call(t *testing.T) {
+	path, cleanup := ipcTestServer(t)
+	defer cleanup()
+
+	client := NewRPCClient(path)
+	defer client.Close()
+
+	blockNumber, err := client.Eth.BlockNumber()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if blockNumber != 1207 {
+		t.Errorf("wrong block number [Expected: %v, Actual: %v]", 1207, blockNumber)
+	}
+
+	_, err = client.Eth.Hashrate()
+	rpcError, ok := err.(*RPCError)
+	if !ok || rpcError.Code != -32601 {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", -32601, err)
+	}
+}
+
+func TestIPCTransport_Batch(t *testing.T) {
+	path, cleanup := ipcTestServer(t)
+	defer cleanup()
+
+	client := NewRPCClient(path)
+	defer client.Close()
+
+	r1 := client.NewRPCRequestObject(MethodEthBlockNumber)
+	r2 := client.NewRPCRequestObject(MethodHashrate)
+
+	batch, err := client.Batch(r1, r2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	response, err := checkRPCError(batch.GetResponseOf(r1))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if response.Result != "0x4b7" {
+		t.Errorf("wrong result [Expected: %v, Actual: %v]", "0x4b7", response.Result)
+	}
+
+	if _, err := checkRPCError(batch.GetResponseOf(r2)); err == nil {
+		t.Errorf("expected an rpc error for %v", MethodHashrate)
+	}
+}
@@ -0,0 +1,344 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// streamConn is a persistent, message oriented connection to an rpc backend.
+// WriteMessage is never called concurrently, ReadMessage is only called from a single reader goroutine.
+type streamConn interface {
+	WriteMessage(ctx context.Context, message []byte) error
+	ReadMessage() ([]byte, error)
+	Close() error
+}
+
+type streamDialer func(ctx context.Context) (streamConn, error)
+
+// streamMessage is any jsonrpc message a backend can send over a persistent connection:
+// a response (ID set) or a server side notification (Method set, no ID).
+type streamMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *uint           `json:"id"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+type streamReply struct {
+	response *RPCResponse
+	err      error
+}
+
+// streamCall is a request waiting for its response on a specific connection.
+// onResponse, if set, is run on the reader goroutine before any later message is handled.
+type streamCall struct {
+	conn       streamConn
+	reply      chan streamReply
+	onResponse func(response *RPCResponse)
+}
+
+// streamTransport multiplexes concurrent requests over a single persistent connection.
+//
+// Every outgoing request gets a transport-internal id, so responses can be matched even if the
+// Client does not auto increment its ids. The id the caller used is restored on the response.
+// The connection is dialed lazily and dialed again on the next request after it dropped;
+// requests that were in flight while the connection dropped fail with the read error.
+// If subscriptions are active, the connection is re-established right away and all subscriptions are renewed.
+type streamTransport struct {
+	dial streamDialer
+
+	dialMu  sync.Mutex
+	writeMu sync.Mutex
+
+	mu            sync.Mutex
+	conn          streamConn
+	closed        bool
+	nextID        uint
+	pending       map[uint]*streamCall
+	subscriptions map[string]*Subscription
+	resubscribing bool
+}
+
+func newStreamTransport(dial streamDialer) *streamTransport {
+	return &streamTransport{
+		dial:          dial,
+		pending:       make(map[uint]*streamCall),
+		subscriptions: make(map[string]*Subscription),
+	}
+}
+
+func (t *streamTransport) Call(ctx context.Context, request *RPCRequest) (*RPCResponse, error) {
+	return t.call(ctx, request, nil)
+}
+
+func (t *streamTransport) call(ctx context.Context, request *RPCRequest, onResponse func(response *RPCResponse)) (*RPCResponse, error) {
+	responses, err := t.roundTrip(ctx, []interface{}{request}, false, onResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return &responses[0], nil
+}
+
+func (t *streamTransport) Notify(ctx context.Context, notification *RPCNotification) error {
+	_, err := t.roundTrip(ctx, []interface{}{notification}, false, nil)
+	return err
+}
+
+func (t *streamTransport) Batch(ctx context.Context, requests []interface{}) ([]RPCResponse, error) {
+	return t.roundTrip(ctx, requests, true, nil)
+}
+
+func (t *streamTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	conn := t.conn
+	t.conn = nil
+	subscriptions := t.subscriptions
+	t.subscriptions = make(map[string]*Subscription)
+	t.mu.Unlock()
+
+	t.failPending(nil, TransportClosed)
+
+	for _, sub := range subscriptions {
+		sub.fail(TransportClosed)
+	}
+
+	if conn != nil {
+		return conn.Close()
+	}
+
+	return nil
+}
+
+func (t *streamTransport) roundTrip(ctx context.Context, requests []interface{}, batch bool, onResponse func(response *RPCResponse)) ([]RPCResponse, error) {
+	conn, err := t.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	outgoing := make([]interface{}, len(requests))
+	callerIDs := make([]uint, 0, len(requests))
+	internalIDs := make([]uint, 0, len(requests))
+	replies := make([]chan streamReply, 0, len(requests))
+
+	for k, r := range requests {
+		switch r := r.(type) {
+		case *RPCRequest:
+			id, reply := t.register(conn, onResponse)
+			request := *r
+			request.ID = id
+			outgoing[k] = &request
+			callerIDs = append(callerIDs, r.ID)
+			internalIDs = append(internalIDs, id)
+			replies = append(replies, reply)
+		case *RPCNotification:
+			outgoing[k] = r
+		default:
+			t.unregister(internalIDs...)
+			return nil, fmt.Errorf("Invalid parameter: %s", r)
+		}
+	}
+
+	var body []byte
+	if batch {
+		body, err = json.Marshal(outgoing)
+	} else {
+		body, err = json.Marshal(outgoing[0])
+	}
+
+	if err != nil {
+		t.unregister(internalIDs...)
+		return nil, err
+	}
+
+	t.writeMu.Lock()
+	err = conn.WriteMessage(ctx, body)
+	t.writeMu.Unlock()
+
+	if err != nil {
+		t.unregister(internalIDs...)
+		t.drop(conn, err)
+		return nil, contextError(ctx, err)
+	}
+
+	responses := make([]RPCResponse, len(replies))
+
+	for k, reply := range replies {
+		select {
+		case r := <-reply:
+			if r.err != nil {
+				t.unregister(internalIDs...)
+				return nil, r.err
+			}
+			responses[k] = *r.response
+			responses[k].ID = callerIDs[k]
+		case <-ctx.Done():
+			t.unregister(internalIDs...)
+			return nil, ctx.Err()
+		}
+	}
+
+	return responses, nil
+}
+
+func (t *streamTransport) connect(ctx context.Context) (streamConn, error) {
+	t.dialMu.Lock()
+	defer t.dialMu.Unlock()
+
+	t.mu.Lock()
+	conn, closed := t.conn, t.closed
+	t.mu.Unlock()
+
+	if closed {
+		return nil, TransportClosed
+	}
+
+	if conn != nil {
+		return conn, nil
+	}
+
+	conn, err := t.dial(ctx)
+	if err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		conn.Close()
+		return nil, TransportClosed
+	}
+	t.conn = conn
+	t.mu.Unlock()
+
+	go t.read(conn)
+
+	return conn, nil
+}
+
+func (t *streamTransport) read(conn streamConn) {
+	for {
+		message, err := conn.ReadMessage()
+		if err != nil {
+			t.drop(conn, err)
+			return
+		}
+
+		t.handle(message)
+	}
+}
+
+// drop forgets the connection and fails all requests waiting on it.
+// It is a no-op if the connection was already replaced or the transport was closed.
+func (t *streamTransport) drop(conn streamConn, cause error) {
+	t.mu.Lock()
+	if t.conn != conn {
+		t.mu.Unlock()
+		return
+	}
+	t.conn = nil
+	t.mu.Unlock()
+
+	conn.Close()
+	t.failPending(conn, fmt.Errorf("rpc connection lost: %v", cause))
+	t.startResubscribe()
+}
+
+func (t *streamTransport) handle(message []byte) {
+	message = bytes.TrimSpace(message)
+
+	if len(message) > 0 && message[0] == '[' {
+		list := make([]json.RawMessage, 0)
+		if err := json.Unmarshal(message, &list); err != nil {
+			return
+		}
+		for _, m := range list {
+			t.handle(m)
+		}
+		return
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(message))
+	decoder.UseNumber()
+	msg := streamMessage{}
+	if err := decoder.Decode(&msg); err != nil {
+		return
+	}
+
+	if msg.ID == nil {
+		if strings.HasSuffix(msg.Method, "_subscription") {
+			t.dispatch(msg.Params)
+		}
+		return
+	}
+
+	t.mu.Lock()
+	call, ok := t.pending[*msg.ID]
+	delete(t.pending, *msg.ID)
+	t.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	response := &RPCResponse{
+		JSONRPC: msg.JSONRPC,
+		Result:  msg.Result,
+		Error:   msg.Error,
+		ID:      *msg.ID,
+	}
+
+	if call.onResponse != nil {
+		call.onResponse(response)
+	}
+
+	call.reply <- streamReply{response: response}
+}
+
+func (t *streamTransport) register(conn streamConn, onResponse func(response *RPCResponse)) (uint, chan streamReply) {
+	reply := make(chan streamReply, 1)
+
+	t.mu.Lock()
+	id := t.nextID
+	t.nextID++
+	t.pending[id] = &streamCall{conn: conn, reply: reply, onResponse: onResponse}
+	t.mu.Unlock()
+
+	return id, reply
+}
+
+func (t *streamTransport) unregister(ids ...uint) {
+	t.mu.Lock()
+	for _, id := range ids {
+		delete(t.pending, id)
+	}
+	t.mu.Unlock()
+}
+
+// failPending fails all requests waiting on conn, or all waiting requests if conn is nil.
+func (t *streamTransport) failPending(conn streamConn, err error) {
+	t.mu.Lock()
+	failed := make([]*streamCall, 0)
+	for id, call := range t.pending {
+		if conn == nil || call.conn == conn {
+			failed = append(failed, call)
+			delete(t.pending, id)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, call := range failed {
+		call.reply <- streamReply{err: err}
+	}
+}
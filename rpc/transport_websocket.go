@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	websocketHandshakeTimeout = 10 * time.Second
+	websocketWriteTimeout     = 10 * time.Second
+	websocketPingInterval     = 30 * time.Second
+	websocketPongWait         = 60 * time.Second
+)
+
+// websocketConn is a streamConn over a websocket connection. It pings the backend every
+// websocketPingInterval and treats the connection as dropped if nothing, not even a pong,
+// was received for websocketPongWait.
+type websocketConn struct {
+	conn      *websocket.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newWebsocketTransport(client *Client) *streamTransport {
+	return newStreamTransport(func(ctx context.Context) (streamConn, error) {
+		header := http.Header{}
+		for k, v := range client.customHeaders {
+			header.Add(k, v)
+		}
+
+		return dialWebsocket(ctx, client.endpoint, header)
+	})
+}
+
+func dialWebsocket(ctx context.Context, endpoint string, header http.Header) (*websocketConn, error) {
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: websocketHandshakeTimeout,
+	}
+
+	conn, _, err := dialer.DialContext(ctx, endpoint, header)
+	if err != nil {
+		return nil, err
+	}
+
+	wc := &websocketConn{
+		conn: conn,
+		done: make(chan struct{}),
+	}
+
+	conn.SetReadDeadline(time.Now().Add(websocketPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(websocketPongWait))
+	})
+
+	go wc.ping()
+
+	return wc, nil
+}
+
+func (wc *websocketConn) WriteMessage(ctx context.Context, message []byte) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(websocketWriteTimeout)
+	}
+
+	wc.conn.SetWriteDeadline(deadline)
+
+	return wc.conn.WriteMessage(websocket.TextMessage, message)
+}
+
+func (wc *websocketConn) ReadMessage() ([]byte, error) {
+	_, message, err := wc.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	wc.conn.SetReadDeadline(time.Now().Add(websocketPongWait))
+
+	return message, nil
+}
+
+func (wc *websocketConn) Close() error {
+	err := error(nil)
+	wc.closeOnce.Do(func() {
+		close(wc.done)
+		err = wc.conn.Close()
+	})
+	return err
+}
+
+func (wc *websocketConn) ping() {
+	ticker := time.NewTicker(websocketPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// WriteControl may be called concurrently to WriteMessage
+			if err := wc.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteTimeout)); err != nil {
+				return
+			}
+		case <-wc.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// websocketTestServer answers every request with its first param as result.
+// Requests are collected in groups of `window` and answered in reverse order.
+func websocketTestServer(t *testing.T, window int, dropFirst bool) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	var mu sync.Mutex
+	connections := 0
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		mu.Lock()
+		connections++
+		drop := dropFirst && connections == 1
+		mu.Unlock()
+
+		for {
+			requests := make([]RPCRequest, 0, window)
+			for len(requests) < window {
+				_, message, err := conn.ReadMessage()
+				if err != nil {
+					return
+				}
+				if drop {
+					return
+				}
+				request := RPCRequest{}
+				if err := json.Unmarshal(message, &request); err != nil {
+					t.Error(err)
+					return
+				}
+				requests = append(requests, request)
+			}
+
+			for i := len(requests) - 1; i >= 0; i-- {
+				params := requests[i].Params.([]interface{})
+				response := RPCResponse{JSONRPC: "2.0", ID: requests[i].ID, Result: params[0]}
+				if err := conn.WriteJSON(response); err != nil {
+					return
+				}
+			}
+		}
+	}))
+}
+
+func websocketEndpoint(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestWebsocketTransport_ConcurrentCalls(t *testing.T) {
+	callers := 8
+	server := websocketTestServer(t, callers, false)
+	defer server.Close()
+
+	client := NewRPCClient(websocketEndpoint(server))
+	defer client.Close()
+	// all requests carry the same id, the transport has to keep them apart
+	client.SetAutoIncrementID(false)
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			expected := strings.Repeat("a", i+1)
+			result, err := client.RequestString("test_echo", expected)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			if result != expected {
+				t.Errorf("wrong result [Expected: %v, Actual: %v]", expected, result)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestWebsocketTransport_Reconnect(t *testing.T) {
+	server := websocketTestServer(t, 1, true)
+	defer server.Close()
+
+	client := NewRPCClient(websocketEndpoint(server))
+	defer client.Close()
+
+	if _, err := client.RequestString("test_echo", "lost"); err == nil {
+		t.Errorf("expected an error for the dropped connection")
+		return
+	}
+
+	result, err := client.RequestString("test_echo", "reconnected")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if result != "reconnected" {
+		t.Errorf("wrong result [Expected: %v, Actual: %v]", "reconnected", result)
+	}
+}
+
+func TestWebsocketTransport_Closed(t *testing.T) {
+	server := websocketTestServer(t, 1, false)
+	defer server.Close()
+
+	client := NewRPCClient(websocketEndpoint(server))
+	client.Close()
+
+	if _, err := client.Call("test_echo", "closed"); err != TransportClosed {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", TransportClosed, err)
+	}
+}
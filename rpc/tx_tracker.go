@@ -0,0 +1,254 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	DefaultTrackerPollInterval = 2 * time.Second
+	DefaultPriceBump           = 10 // percent, the minimum geth accepts for a replacement transaction
+
+	cancelGas = 21000
+)
+
+// TransactionTracker follows sent transactions until they are mined and confirmed.
+//
+// New blocks are taken from a newHeads subscription when the transport supports subscriptions,
+// otherwise (or after the subscription failed) the node is polled every PollInterval.
+// A transaction whose including block is reorganized out is followed further until it is included again;
+// OnReorg, if set, is called with the receipt which became invalid.
+type TransactionTracker struct {
+	client *Client
+
+	PollInterval time.Duration
+	PriceBump    int64 // percent by which SpeedUp and Cancel raise the gas prices
+	OnReorg      func(removed *rpctypes.EtherTransactionReceipt)
+}
+
+func NewTransactionTracker(client *Client) *TransactionTracker {
+	return &TransactionTracker{
+		client:       client,
+		PollInterval: DefaultTrackerPollInterval,
+		PriceBump:    DefaultPriceBump,
+	}
+}
+
+// WaitMined blocks until the transaction is included in a block and returns its receipt.
+func (tt *TransactionTracker) WaitMined(ctx context.Context, hash string) (*rpctypes.EtherTransactionReceipt, error) {
+	return tt.wait(ctx, []string{hash}, 1)
+}
+
+// WaitMinedAny is like WaitMined but takes the hashes of a transaction and its replacements (see SpeedUp and Cancel)
+// and returns the receipt of whichever is mined.
+func (tt *TransactionTracker) WaitMinedAny(ctx context.Context, hashes []string) (*rpctypes.EtherTransactionReceipt, error) {
+	if len(hashes) == 0 {
+		return nil, errors.New("no transaction hash given")
+	}
+
+	return tt.wait(ctx, hashes, 1)
+}
+
+// WaitConfirmed blocks until the block including the transaction has the given number of confirmations
+// and returns the receipt. The including block counts as the first confirmation, so 1 is the same as WaitMined.
+func (tt *TransactionTracker) WaitConfirmed(ctx context.Context, hash string, confirmations int64) (*rpctypes.EtherTransactionReceipt, error) {
+	return tt.wait(ctx, []string{hash}, confirmations)
+}
+
+func (tt *TransactionTracker) wait(ctx context.Context, hashes []string, confirmations int64) (*rpctypes.EtherTransactionReceipt, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	heads := make(chan rpctypes.EtherBlock, 1)
+
+	var tick <-chan time.Time
+	var subErr <-chan error
+
+	startPolling := func() {
+		ticker := time.NewTicker(tt.pollInterval())
+		tick = ticker.C
+		go func() {
+			<-ctx.Done()
+			ticker.Stop()
+		}()
+	}
+
+	sub, err := tt.client.Eth.SubscribeNewHeadsContext(ctx, heads)
+	if err != nil {
+		startPolling()
+	} else {
+		defer sub.Unsubscribe()
+		subErr = sub.Err()
+	}
+
+	var included *rpctypes.EtherTransactionReceipt
+
+	for {
+		receipt, err := tt.receipt(ctx, hashes)
+		if err != nil {
+			return nil, err
+		}
+
+		if included != nil && (receipt == nil || !receipt.BlockHash.IsEqual(&included.BlockHash)) && tt.OnReorg != nil {
+			tt.OnReorg(included)
+		}
+		included = receipt
+
+		if receipt != nil {
+			if confirmations <= 1 {
+				return receipt, nil
+			}
+
+			head, err := tt.client.Eth.BlockNumberContext(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			if head-receipt.BlockNumber+1 >= confirmations {
+				return receipt, nil
+			}
+		}
+
+		select {
+		case <-heads:
+		case <-tick:
+		case <-subErr:
+			subErr = nil
+			startPolling()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (tt *TransactionTracker) pollInterval() time.Duration {
+	if tt.PollInterval <= 0 {
+		return DefaultTrackerPollInterval
+	}
+
+	return tt.PollInterval
+}
+
+// receipt returns the first receipt found for the hashes or nil if none of them is mined yet.
+func (tt *TransactionTracker) receipt(ctx context.Context, hashes []string) (*rpctypes.EtherTransactionReceipt, error) {
+	for _, hash := range hashes {
+		response, err := checkRPCError(tt.client.CallContext(ctx, MethodGetTransactionReceipt, hash))
+		if err != nil {
+			return nil, err
+		}
+
+		if response.Result == nil {
+			continue
+		}
+
+		js, err := json.Marshal(response.Result)
+		if err != nil {
+			return nil, err
+		}
+
+		return new(rpctypes.TransactionReceiptRaw).FromJSON(js)
+	}
+
+	return nil, nil
+}
+
+// SpeedUp signs the transaction again with the same nonce and the gas prices raised by PriceBump percent
+// and submits it, replacing the pending original. The original may still be mined first, use WaitMinedAny
+// with both hashes. tx is not modified.
+func (tt *TransactionTracker) SpeedUp(tx *RawTransaction, key *ecdsa.PrivateKey) (*SignedTransaction, error) {
+	return tt.SpeedUpContext(context.Background(), tx, key)
+}
+
+// SpeedUpContext is like SpeedUp but takes a context.Context for deadlines and cancellation.
+func (tt *TransactionTracker) SpeedUpContext(ctx context.Context, tx *RawTransaction, key *ecdsa.PrivateKey) (*SignedTransaction, error) {
+	if tx == nil {
+		return nil, errors.New("transaction cannot be nil")
+	}
+
+	replacement := *tx
+	tt.bumpPrices(&replacement)
+
+	return tt.replace(ctx, &replacement, key)
+}
+
+// Cancel replaces the pending transaction with a transfer of 0 ether from the sender to itself,
+// using the same nonce and the gas prices raised by PriceBump percent. tx is not modified.
+func (tt *TransactionTracker) Cancel(tx *RawTransaction, key *ecdsa.PrivateKey) (*SignedTransaction, error) {
+	return tt.CancelContext(context.Background(), tx, key)
+}
+
+// CancelContext is like Cancel but takes a context.Context for deadlines and cancellation.
+func (tt *TransactionTracker) CancelContext(ctx context.Context, tx *RawTransaction, key *ecdsa.PrivateKey) (*SignedTransaction, error) {
+	if tx == nil {
+		return nil, errors.New("transaction cannot be nil")
+	}
+	if key == nil {
+		return nil, errors.New("private key cannot be nil")
+	}
+
+	self, err := new(rpctypes.EtherAddress).FromString(crypto.PubkeyToAddress(key.PublicKey).Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := *tx
+	replacement.To = self
+	replacement.Value = big.NewInt(0)
+	replacement.Data = nil
+	replacement.AccessList = nil
+	replacement.Gas = cancelGas
+	tt.bumpPrices(&replacement)
+
+	return tt.replace(ctx, &replacement, key)
+}
+
+func (tt *TransactionTracker) replace(ctx context.Context, tx *RawTransaction, key *ecdsa.PrivateKey) (*SignedTransaction, error) {
+	if key == nil {
+		return nil, errors.New("private key cannot be nil")
+	}
+
+	signed, err := tx.Sign(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tt.client.Eth.SendSignedTransactionContext(ctx, signed); err != nil && !IsAlreadyKnownError(err) {
+		return nil, err
+	}
+
+	return signed, nil
+}
+
+func (tt *TransactionTracker) bumpPrices(tx *RawTransaction) {
+	if tx.Type == rpctypes.TransactionTypeDynamicFee {
+		tx.MaxFeePerGas = bumpPrice(tx.MaxFeePerGas, tt.PriceBump)
+		tx.MaxPriorityFeePerGas = bumpPrice(tx.MaxPriorityFeePerGas, tt.PriceBump)
+		return
+	}
+
+	tx.GasPrice = bumpPrice(tx.GasPrice, tt.PriceBump)
+}
+
+// bumpPrice raises price by percent, rounded up and by at least 1 wei.
+func bumpPrice(price *big.Int, percent int64) *big.Int {
+	if price == nil {
+		price = new(big.Int)
+	}
+
+	bumped := new(big.Int).Mul(price, big.NewInt(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	bumped.Div(bumped, big.NewInt(100))
+
+	if bumped.Cmp(price) <= 0 {
+		bumped.Add(price, big.NewInt(1))
+	}
+
+	return bumped
+}
@@ -0,0 +1,221 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	trackerTxHash = "0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b"
+	trackerBlockA = "0x2222222222222222222222222222222222222222222222222222222222222222"
+	trackerBlockB = "0x3333333333333333333333333333333333333333333333333333333333333333"
+)
+
+func trackerReceipt(blockNumber, blockHash string) map[string]interface{} {
+	return map[string]interface{}{
+		"transactionHash":   trackerTxHash,
+		"transactionIndex":  "0x0",
+		"blockNumber":       blockNumber,
+		"blockHash":         blockHash,
+		"from":              "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f",
+		"to":                "0x3535353535353535353535353535353535353535",
+		"cumulativeGasUsed": "0x5208",
+		"gasUsed":           "0x5208",
+		"contractAddress":   nil,
+		"status":            "0x1",
+		"logsBloom":         "0x00",
+		"logs":              []interface{}{},
+	}
+}
+
+// trackerTestServer answers eth_getTransactionReceipt and eth_blockNumber with the given results one after the other
+// (the last one repeatedly) and records the raw transactions sent with eth_sendRawTransaction.
+func trackerTestServer(receipts []interface{}, heads []string) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var receiptCalls, headCalls int
+	sent := make([]string, 0)
+
+	next := func(calls *int, count int) int {
+		call := *calls
+		*calls++
+		if call >= count {
+			call = count - 1
+		}
+		return call
+	}
+
+	server, _ := testServer(func(request RPCRequest) RPCResponse {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch request.Method {
+		case MethodGetTransactionReceipt:
+			return RPCResponse{Result: receipts[next(&receiptCalls, len(receipts))]}
+		case MethodEthBlockNumber:
+			return RPCResponse{Result: heads[next(&headCalls, len(heads))]}
+		case MethodSendRawTransaction:
+			sent = append(sent, request.Params.([]interface{})[0].(string))
+			return RPCResponse{Result: trackerTxHash}
+		}
+		return RPCResponse{}
+	})
+
+	return server, &sent
+}
+
+func testTracker(url string) *TransactionTracker {
+	tracker := NewTransactionTracker(NewRPCClient(url))
+	tracker.PollInterval = 5 * time.Millisecond
+	return tracker
+}
+
+func TestTransactionTracker_WaitMined(t *testing.T) {
+	server, _ := trackerTestServer([]interface{}{nil, nil, trackerReceipt("0x10", trackerBlockA)}, []string{"0x10"})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	receipt, err := testTracker(server.URL).WaitMined(ctx, trackerTxHash)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if receipt.BlockNumber != 16 {
+		t.Errorf("wrong block number [Expected: %v, Actual: %v]", 16, receipt.BlockNumber)
+	}
+}
+
+func TestTransactionTracker_WaitConfirmed(t *testing.T) {
+	server, _ := trackerTestServer([]interface{}{trackerReceipt("0x10", trackerBlockA)}, []string{"0x10", "0x11", "0x12"})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	receipt, err := testTracker(server.URL).WaitConfirmed(ctx, trackerTxHash, 3)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if receipt.BlockHash.String() != trackerBlockA {
+		t.Errorf("wrong block hash [Expected: %v, Actual: %v]", trackerBlockA, receipt.BlockHash.String())
+	}
+
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("returned before the third confirmation [Expected: >= %v, Actual: %v]", 10*time.Millisecond, elapsed)
+	}
+}
+
+func TestTransactionTracker_Reorg(t *testing.T) {
+	receipts := []interface{}{trackerReceipt("0x10", trackerBlockA), nil, trackerReceipt("0x11", trackerBlockB)}
+	server, _ := trackerTestServer(receipts, []string{"0x10", "0x12"})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	removed := make([]string, 0)
+	tracker := testTracker(server.URL)
+	tracker.OnReorg = func(receipt *rpctypes.EtherTransactionReceipt) {
+		removed = append(removed, receipt.BlockHash.String())
+	}
+
+	receipt, err := tracker.WaitConfirmed(ctx, trackerTxHash, 2)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if receipt.BlockHash.String() != trackerBlockB {
+		t.Errorf("wrong block hash [Expected: %v, Actual: %v]", trackerBlockB, receipt.BlockHash.String())
+	}
+
+	if len(removed) != 1 || removed[0] != trackerBlockA {
+		t.Errorf("wrong reorged receipts [Expected: %v, Actual: %v]", []string{trackerBlockA}, removed)
+	}
+}
+
+func TestTransactionTracker_WaitMinedContextCanceled(t *testing.T) {
+	server, _ := trackerTestServer([]interface{}{nil}, []string{"0x10"})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	if _, err := testTracker(server.URL).WaitMined(ctx, trackerTxHash); err != context.DeadlineExceeded {
+		t.Errorf("wrong error [Expected: %v, Actual: %v]", context.DeadlineExceeded, err)
+	}
+}
+
+func TestTransactionTracker_SpeedUpAndCancel(t *testing.T) {
+	server, sent := trackerTestServer([]interface{}{nil}, []string{"0x10"})
+	defer server.Close()
+
+	key, err := crypto.HexToECDSA("4646464646464646464646464646464646464646464646464646464646464646")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tracker := testTracker(server.URL)
+	tx := eip155Transaction()
+
+	faster, err := tracker.SpeedUp(tx, key)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if faster.Transaction.GasPrice.Cmp(big.NewInt(22000000000)) != 0 || faster.Transaction.Nonce != 9 {
+		t.Errorf("wrong replacement [Expected: %v, Actual: %v]", "nonce 9, gas price 22000000000",
+			[]interface{}{faster.Transaction.Nonce, faster.Transaction.GasPrice})
+	}
+
+	if tx.GasPrice.Cmp(big.NewInt(20000000000)) != 0 {
+		t.Errorf("original transaction modified [Expected: %v, Actual: %v]", 20000000000, tx.GasPrice)
+	}
+
+	canceled, err := tracker.Cancel(tx, key)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if !strings.EqualFold(canceled.Transaction.To.String(), canceled.From.String()) || canceled.Transaction.Value.Sign() != 0 {
+		t.Errorf("cancel is no empty self transfer [Expected: %v, Actual: %v]", canceled.From.String(), canceled.Transaction.To.String())
+	}
+
+	if len(*sent) != 2 || (*sent)[0] != faster.Raw.String() || (*sent)[1] != canceled.Raw.String() {
+		t.Errorf("wrong transactions sent [Expected: %v, Actual: %v]", []string{faster.Raw.String(), canceled.Raw.String()}, *sent)
+	}
+}
+
+func TestBumpPrice(t *testing.T) {
+	tests := []struct {
+		price    *big.Int
+		expected int64
+	}{
+		{big.NewInt(100), 110},
+		{big.NewInt(101), 112},
+		{big.NewInt(1), 2},
+		{nil, 1},
+	}
+
+	for _, test := range tests {
+		if bumped := bumpPrice(test.price, 10); bumped.Int64() != test.expected {
+			t.Errorf("wrong bumped price of %v [Expected: %v, Actual: %v]", test.price, test.expected, bumped)
+		}
+	}
+}
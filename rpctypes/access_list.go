@@ -0,0 +1,83 @@
+package rpctypes
+
+import "fmt"
+
+// transaction types of EIP-2718 envelopes
+const (
+	TransactionTypeLegacy     = 0
+	TransactionTypeAccessList = 1 // EIP-2930
+	TransactionTypeDynamicFee = 2 // EIP-1559
+)
+
+// AccessTuple is an address and the storage slots of it a transaction declares to access (EIP-2930).
+type AccessTuple struct {
+	Address     EtherAddress `json:"address"`
+	StorageKeys []HexString  `json:"storageKeys"`
+}
+
+type AccessList []AccessTuple
+
+type AccessTupleRaw struct {
+	Address     string   `json:"address"`
+	StorageKeys []string `json:"storageKeys"`
+}
+
+func (raw AccessTupleRaw) ToAccessTuple() (*AccessTuple, error) {
+	address, err := new(EtherAddress).FromString(raw.Address)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing access list address, %v", err)
+	}
+
+	keys, err := ToHexStringList(raw.StorageKeys)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing access list storage keys, %v", err)
+	}
+
+	return &AccessTuple{Address: *address, StorageKeys: keys}, nil
+}
+
+func ToAccessList(raw []AccessTupleRaw) (AccessList, error) {
+	list := make(AccessList, len(raw))
+
+	for k, v := range raw {
+		tuple, err := v.ToAccessTuple()
+		if err != nil {
+			return nil, err
+		}
+		list[k] = *tuple
+	}
+
+	return list, nil
+}
+
+// Params returns the access list in the format of the json rpc api.
+func (list AccessList) Params() []AccessTupleRaw {
+	raw := make([]AccessTupleRaw, len(list))
+
+	for k, tuple := range list {
+		keys := make([]string, len(tuple.StorageKeys))
+		for i, key := range tuple.StorageKeys {
+			keys[i] = key.Hash()
+		}
+		raw[k] = AccessTupleRaw{Address: tuple.Address.Hash(), StorageKeys: keys}
+	}
+
+	return raw
+}
+
+func (list1 AccessList) Compare(list2 AccessList) error {
+	if len(list1) != len(list2) {
+		return fmt.Errorf("wrong access list sizes, left: %v, right: %v", len(list1), len(list2))
+	}
+
+	for k, tuple := range list1 {
+		if !tuple.Address.IsEqual(&list2[k].Address) {
+			return fmt.Errorf("error in access list address at %v: [1: %v,2: %v]", k, tuple.Address.String(), list2[k].Address.String())
+		}
+		if err := CompareHexStringList(tuple.StorageKeys, list2[k].StorageKeys); err != nil {
+			return fmt.Errorf("error in access list storage keys at %v, %v", k, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package rpctypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+const (
+	EmptyCodeHash = "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470" // keccak256 of empty code
+	EmptyRootHash = "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421" // root of an empty trie
+)
+
+// AccountProof is the result of eth_getProof: an account and the merkle patricia proofs of it and some of its storage slots.
+type AccountProof struct {
+	Address      EtherAddress   `json:"address"`
+	Balance      *big.Int       `json:"balance"`
+	Nonce        uint64         `json:"nonce"`
+	CodeHash     HexString      `json:"codeHash"`
+	StorageHash  HexString      `json:"storageHash"`  // the storage root of the account
+	AccountProof []HexString    `json:"accountProof"` // the rlp encoded trie nodes from the state root to the account
+	StorageProof []StorageProof `json:"storageProof"`
+}
+
+// StorageProof proves the value of one storage slot against the storage root of the account.
+type StorageProof struct {
+	Key   HexString   `json:"key"`
+	Value *big.Int    `json:"value"`
+	Proof []HexString `json:"proof"` // the rlp encoded trie nodes from the storage root to the slot
+}
+
+// HasCode reports whether the account is a contract.
+func (proof *AccountProof) HasCode() bool {
+	return proof.CodeHash.BigInt().Sign() != 0 && proof.CodeHash.Hash() != EmptyCodeHash
+}
+
+type AccountProofRaw struct {
+	Address      string            `json:"address"`
+	Balance      string            `json:"balance"`
+	Nonce        string            `json:"nonce"`
+	CodeHash     string            `json:"codeHash"`
+	StorageHash  string            `json:"storageHash"`
+	AccountProof []string          `json:"accountProof"`
+	StorageProof []StorageProofRaw `json:"storageProof"`
+}
+
+type StorageProofRaw struct {
+	Key   string   `json:"key"`
+	Value string   `json:"value"`
+	Proof []string `json:"proof"`
+}
+
+func (raw *AccountProofRaw) FromJSON(js []byte) (*AccountProof, error) {
+	raw = new(AccountProofRaw)
+	err := json.Unmarshal(js, raw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return raw.ToAccountProof()
+}
+
+func (raw *AccountProofRaw) ToAccountProof() (*AccountProof, error) {
+	address, err := new(EtherAddress).FromString(raw.Address)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing address, %v", err)
+	}
+
+	balance, err := HexToBigInt(raw.Balance)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing balance, %v", err)
+	}
+
+	nonce, err := HexToBigInt(raw.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing nonce, %v", err)
+	}
+
+	codeHash, err := NewHexString(raw.CodeHash)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing codeHash, %v", err)
+	}
+
+	storageHash, err := NewHexString(raw.StorageHash)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing storageHash, %v", err)
+	}
+
+	accountProof, err := ToHexStringList(raw.AccountProof)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing accountProof, %v", err)
+	}
+
+	storageProof := make([]StorageProof, len(raw.StorageProof))
+	for k, v := range raw.StorageProof {
+		proof, err := v.ToStorageProof()
+		if err != nil {
+			return nil, err
+		}
+		storageProof[k] = *proof
+	}
+
+	return &AccountProof{
+		Address:      *address,
+		Balance:      balance,
+		Nonce:        nonce.Uint64(),
+		CodeHash:     *codeHash,
+		StorageHash:  *storageHash,
+		AccountProof: accountProof,
+		StorageProof: storageProof,
+	}, nil
+}
+
+func (raw StorageProofRaw) ToStorageProof() (*StorageProof, error) {
+	key, err := NewHexString(raw.Key)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing storage proof key, %v", err)
+	}
+
+	value, err := HexToBigInt(raw.Value)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing storage proof value of %v, %v", raw.Key, err)
+	}
+
+	proof, err := ToHexStringList(raw.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing storage proof of %v, %v", raw.Key, err)
+	}
+
+	return &StorageProof{Key: *key, Value: value, Proof: proof}, nil
+}
@@ -43,6 +43,16 @@ func HexToBigInt(s string) (*big.Int, error) {
 	return new(big.Int).SetBytes(b), nil
 }
 
+/*
+	returns a big int in the quantity format of the json rpc api: '0x' followed by the hex value without leading zeros
+ */
+func BigIntToHex(b *big.Int) string {
+	if b == nil || b.Sign() == 0 {
+		return "0x0"
+	}
+	return "0x" + b.Text(16)
+}
+
 /*
 	converts an interface list to a string list
  */
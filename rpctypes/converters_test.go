@@ -70,3 +70,19 @@ func TestHexToByte(t *testing.T) {
 		t.Error(fmt.Errorf("error in parsing string Result: %v", result))
 	}
 }
+
+func TestBigIntToHex(t *testing.T) {
+	tests := map[string]*big.Int{
+		"0x0":                 nil,
+		"0x1":                 big.NewInt(1),
+		"0x5208":              big.NewInt(21000),
+		"0xde0b6b3a7640000":   big.NewInt(1000000000000000000),
+		"0x10000000000000000": new(big.Int).Lsh(big.NewInt(1), 64),
+	}
+
+	for expected, b := range tests {
+		if actual := BigIntToHex(b); actual != expected {
+			t.Errorf("[Expected: %v, Actual: %v]", expected, actual)
+		}
+	}
+}
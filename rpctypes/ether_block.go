@@ -26,6 +26,7 @@ type EtherBlock struct {
 	Transactions     []HexString        `json:"transactions"`      // Array of transaction objects, or 32 Bytes transaction hashes depending on the last given parameter
 	TransactionsFull []EtherTransaction `json:"transactions_full"` // Array of transaction objects, or 32 Bytes transaction hashes depending on the last given parameter
 	Uncles           []HexString        `json:"uncles"`            // Array - Array of uncle hashes
+	BaseFeePerGas    *big.Int           `json:"base_fee_per_gas"`  // EIP-1559 base fee of this block, nil before the london fork
 }
 
 func (b1 EtherBlock) Compare(b2 *EtherBlock) error {
@@ -89,5 +90,8 @@ func (b1 EtherBlock) Compare(b2 *EtherBlock) error {
 	if err := CompareHexStringList(b1.Uncles, b2.Uncles); err != nil {
 		return fmt.Errorf("error in uncles: [1: %v,2: %v], message, %v", b1.Uncles, b2.Uncles, err.Error())
 	}
+	if (b1.BaseFeePerGas == nil) != (b2.BaseFeePerGas == nil) || (b1.BaseFeePerGas != nil && b1.BaseFeePerGas.Cmp(b2.BaseFeePerGas) != 0) {
+		return fmt.Errorf("error in baseFeePerGas: [1: %v,2: %v]", b1.BaseFeePerGas, b2.BaseFeePerGas)
+	}
 	return nil
 }
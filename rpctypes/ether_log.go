@@ -162,7 +162,7 @@ func (raw *EtherLogRaw) ToEtherLog() (*EtherLog, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error parsing logIndex, %v", err)
 	}
-	log.TransactionIndex = logIndex.Int64()
+	log.LogIndex = logIndex.Int64()
 
 	// Removed
 	log.Removed = raw.Removed
@@ -0,0 +1,15 @@
+package rpctypes
+
+import "testing"
+
+func TestEtherLogRaw_LogIndex(t *testing.T) {
+	log, err := (&EtherLogRaw{Data: "0x", BlockNumber: "0x1", TransactionIndex: "0x2", LogIndex: "0x3", Address: "0x01", BlockHash: "0x01", TransactionHash: "0x01"}).ToEtherLog()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if log.TransactionIndex != 2 || log.LogIndex != 3 {
+		t.Errorf("wrong indexes [Expected: %v %v, Actual: %v %v]", 2, 3, log.TransactionIndex, log.LogIndex)
+	}
+}
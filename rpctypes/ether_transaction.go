@@ -20,6 +20,13 @@ type EtherTransaction struct {
 	V                HexString    `json:"v"`
 	R                HexString    `json:"r"`
 	S                HexString    `json:"s"`
+	// typed transactions (EIP-2718), zero values for legacy transactions
+	Type                 int64      `json:"type"`
+	ChainID              int64      `json:"chainId"`
+	MaxFeePerGas         EtherValue `json:"maxFeePerGas"`         // EIP-1559 only
+	MaxPriorityFeePerGas EtherValue `json:"maxPriorityFeePerGas"` // EIP-1559 only
+	AccessList           AccessList `json:"accessList"`
+	YParity              int64      `json:"yParity"`
 }
 
 func CompareEtherTransactionList(et1 []EtherTransaction, et2 []EtherTransaction, onlyHash bool) error {
@@ -85,5 +92,23 @@ func (et1 EtherTransaction) Compare(et2 *EtherTransaction) error {
 	if !et1.S.IsEqual(&et2.S) {
 		return fmt.Errorf("error in s: [1: %v,2: %v]", et1.S.value, et2.S.value)
 	}
+	if et1.Type != et2.Type {
+		return fmt.Errorf("error in type: [1: %v,2: %v]", et1.Type, et2.Type)
+	}
+	if et1.ChainID != et2.ChainID {
+		return fmt.Errorf("error in chainId: [1: %v,2: %v]", et1.ChainID, et2.ChainID)
+	}
+	if !et1.MaxFeePerGas.IsEqual(&et2.MaxFeePerGas) {
+		return fmt.Errorf("error in maxFeePerGas: [1: %v,2: %v]", et1.MaxFeePerGas.String(), et2.MaxFeePerGas.String())
+	}
+	if !et1.MaxPriorityFeePerGas.IsEqual(&et2.MaxPriorityFeePerGas) {
+		return fmt.Errorf("error in maxPriorityFeePerGas: [1: %v,2: %v]", et1.MaxPriorityFeePerGas.String(), et2.MaxPriorityFeePerGas.String())
+	}
+	if err := et1.AccessList.Compare(et2.AccessList); err != nil {
+		return err
+	}
+	if et1.YParity != et2.YParity {
+		return fmt.Errorf("error in yParity: [1: %v,2: %v]", et1.YParity, et2.YParity)
+	}
 	return nil
 }
@@ -19,6 +19,8 @@ type EtherTransactionReceipt struct {
 	Status            int64        `json:"status"`
 	LogsBloom         HexString    `json:"logsBloom"`
 	Logs              []EtherLog   `json:"logs"`
+	Type              int64        `json:"type"`              // EIP-2718 transaction type, 0 for legacy transactions
+	EffectiveGasPrice EtherValue   `json:"effectiveGasPrice"` // the price per gas actually paid (base fee + tip for EIP-1559)
 }
 
 func (tr1 EtherTransactionReceipt) Compare(tr2 *EtherTransactionReceipt) error {
@@ -58,6 +60,12 @@ func (tr1 EtherTransactionReceipt) Compare(tr2 *EtherTransactionReceipt) error {
 	if err := CompareEtherLogList(tr1.Logs, tr2.Logs); err != nil {
 		return err
 	}
+	if tr1.Type != tr2.Type {
+		return fmt.Errorf("error in type: [1: %v,2: %v]", tr1.Type, tr2.Type)
+	}
+	if !tr1.EffectiveGasPrice.IsEqual(&tr2.EffectiveGasPrice) {
+		return fmt.Errorf("not equal effectiveGasPrice %v %v", tr1.EffectiveGasPrice.String(), tr2.EffectiveGasPrice.String())
+	}
 
 	return nil
 }
@@ -93,7 +101,7 @@ func (raw *TransactionReceiptRaw) ToEtherTransactionReceipt() (*EtherTransaction
 	if err != nil {
 		return nil, fmt.Errorf("error parsing transactinIndex, %v", err)
 	}
-	receipt.BlockNumber = ti.Int64()
+	receipt.TransactionIndex = ti.Int64()
 	// BlockNumber
 	bn, err := NewHexString(raw.BlockNumber)
 	if err != nil {
@@ -162,6 +170,22 @@ func (raw *TransactionReceiptRaw) ToEtherTransactionReceipt() (*EtherTransaction
 		logs[k] = *elog
 	}
 	receipt.Logs = logs
+	// Type (missing before EIP-2718)
+	if raw.Type != "" {
+		tt, err := NewHexString(raw.Type)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing type, %v", err)
+		}
+		receipt.Type = tt.Int64()
+	}
+	// EffectiveGasPrice (missing before EIP-1559)
+	if raw.EffectiveGasPrice != "" {
+		egp, err := new(EtherValue).FromHexString(raw.EffectiveGasPrice)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing effectiveGasPrice, %v", err)
+		}
+		receipt.EffectiveGasPrice = *egp
+	}
 
 	return receipt, nil
 }
@@ -183,4 +207,6 @@ type TransactionReceiptRaw struct {
 	Status            string        `json:"status"`
 	LogsBloom         string        `json:"logsBloom"`
 	Logs              []EtherLogRaw `json:"logs"`
+	Type              string        `json:"type"`
+	EffectiveGasPrice string        `json:"effectiveGasPrice"`
 }
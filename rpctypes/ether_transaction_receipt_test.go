@@ -0,0 +1,24 @@
+package rpctypes
+
+import "testing"
+
+func TestTransactionReceiptRaw_FromJSON(t *testing.T) {
+	js := `{"transactionHash":"0x88df016429689c079f3b2f6ad39fa052532c56795b733da78a91ebe6a713944b","transactionIndex":"0x1","blockNumber":"0xc5043f",` +
+		`"blockHash":"0x1d59ff54b1eb26b013ce3cb5fc9dab3705b415a67127a003c3e61eb445bb8df2","from":"0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f",` +
+		`"to":"0x3535353535353535353535353535353535353535","cumulativeGasUsed":"0xa410","gasUsed":"0x5208","contractAddress":null,"status":"0x1",` +
+		`"logsBloom":"0x00","logs":[],"type":"0x2","effectiveGasPrice":"0x4a817c800"}`
+
+	receipt, err := new(TransactionReceiptRaw).FromJSON([]byte(js))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if receipt.Type != TransactionTypeDynamicFee || receipt.EffectiveGasPrice.BigInt().Int64() != 20000000000 {
+		t.Errorf("wrong typed fields [Expected: %v %v, Actual: %v %v]", 2, 20000000000, receipt.Type, receipt.EffectiveGasPrice.BigInt())
+	}
+
+	if receipt.TransactionIndex != 1 || receipt.BlockNumber != 0xc5043f {
+		t.Errorf("wrong position [Expected: %v %v, Actual: %v %v]", 1, 0xc5043f, receipt.TransactionIndex, receipt.BlockNumber)
+	}
+}
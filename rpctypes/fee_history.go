@@ -0,0 +1,77 @@
+package rpctypes
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// FeeHistory is the result of eth_feeHistory for the blocks OldestBlock up to OldestBlock + len(GasUsedRatio) - 1.
+type FeeHistory struct {
+	OldestBlock   int64        `json:"oldestBlock"`
+	BaseFeePerGas []*big.Int   `json:"baseFeePerGas"` // one entry more than blocks, the last is the base fee of the block after the newest
+	GasUsedRatio  []float64    `json:"gasUsedRatio"`  // gas used / gas limit of every block
+	Reward        [][]*big.Int `json:"reward"`        // the priority fees at the requested percentiles of every block, nil if none were requested
+}
+
+type FeeHistoryRaw struct {
+	OldestBlock   string     `json:"oldestBlock"`
+	BaseFeePerGas []string   `json:"baseFeePerGas"`
+	GasUsedRatio  []float64  `json:"gasUsedRatio"`
+	Reward        [][]string `json:"reward"`
+}
+
+func (raw *FeeHistoryRaw) FromJSON(js []byte) (*FeeHistory, error) {
+	raw = new(FeeHistoryRaw)
+	err := json.Unmarshal(js, raw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return raw.ToFeeHistory()
+}
+
+func (raw *FeeHistoryRaw) ToFeeHistory() (*FeeHistory, error) {
+	oldest, err := NewHexString(raw.OldestBlock)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing oldestBlock, %v", err)
+	}
+
+	baseFees, err := hexToBigIntList(raw.BaseFeePerGas)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing baseFeePerGas, %v", err)
+	}
+
+	history := &FeeHistory{
+		OldestBlock:   oldest.Int64(),
+		BaseFeePerGas: baseFees,
+		GasUsedRatio:  raw.GasUsedRatio,
+	}
+
+	if raw.Reward != nil {
+		history.Reward = make([][]*big.Int, len(raw.Reward))
+		for k, v := range raw.Reward {
+			rewards, err := hexToBigIntList(v)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing reward of block %v, %v", history.OldestBlock+int64(k), err)
+			}
+			history.Reward[k] = rewards
+		}
+	}
+
+	return history, nil
+}
+
+func hexToBigIntList(values []string) ([]*big.Int, error) {
+	list := make([]*big.Int, len(values))
+	for k, v := range values {
+		bi, err := HexToBigInt(v)
+		if err != nil {
+			return nil, err
+		}
+		list[k] = bi
+	}
+
+	return list, nil
+}
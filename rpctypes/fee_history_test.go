@@ -0,0 +1,43 @@
+package rpctypes
+
+import (
+	"testing"
+)
+
+func TestFeeHistoryRaw_FromJSON(t *testing.T) {
+	js := []byte(`{"oldestBlock":"0xa","baseFeePerGas":["0x3b9aca00","0x3b9aca01"],"gasUsedRatio":[0.25],"reward":[["0x1","0x77359400"]]}`)
+
+	history, err := new(FeeHistoryRaw).FromJSON(js)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if history.OldestBlock != 10 {
+		t.Errorf("wrong oldest block [Expected: %v, Actual: %v]", 10, history.OldestBlock)
+	}
+
+	if len(history.BaseFeePerGas) != 2 || history.BaseFeePerGas[1].Int64() != 1000000001 {
+		t.Errorf("wrong base fees [Expected: %v, Actual: %v]", "[1000000000 1000000001]", history.BaseFeePerGas)
+	}
+
+	if len(history.GasUsedRatio) != 1 || history.GasUsedRatio[0] != 0.25 {
+		t.Errorf("wrong gas used ratio [Expected: %v, Actual: %v]", 0.25, history.GasUsedRatio)
+	}
+
+	if len(history.Reward) != 1 || history.Reward[0][1].Int64() != 2000000000 {
+		t.Errorf("wrong rewards [Expected: %v, Actual: %v]", "[[1 2000000000]]", history.Reward)
+	}
+}
+
+func TestFeeHistoryRaw_WithoutReward(t *testing.T) {
+	history, err := new(FeeHistoryRaw).FromJSON([]byte(`{"oldestBlock":"0x1","baseFeePerGas":["0x1","0x1"],"gasUsedRatio":[0.5]}`))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if history.Reward != nil {
+		t.Errorf("rewards without percentiles [Expected: %v, Actual: %v]", nil, history.Reward)
+	}
+}
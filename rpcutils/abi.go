@@ -0,0 +1,525 @@
+package rpcutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	BaseTypeString = "string"
+	BaseTypeTuple  = "tuple"
+)
+
+// ABIKind is the kind of an ABIType.
+type ABIKind int
+
+const (
+	ABIUInt ABIKind = iota
+	ABIInt
+	ABIAddress
+	ABIBool
+	ABIFixedBytes // bytes<M>
+	ABIBytes      // dynamic bytes
+	ABIString
+	ABIFunction // an address followed by a function selector, encoded like bytes24
+	ABIArray    // T[k]
+	ABISlice    // T[]
+	ABITuple
+)
+
+// ABIType is a parsed solidity ABI type, e.g. uint256, bytes32[], (address,string)[2].
+type ABIType struct {
+	Kind       ABIKind
+	Size       int           // bits of uint<M> and int<M>, bytes of bytes<M>
+	Length     int           // number of elements of T[k]
+	Elem       *ABIType      // element type of T[k] and T[]
+	Components []ABIArgument // fields of tuples
+}
+
+// ABIArgument is an input or output of a function, error or event.
+type ABIArgument struct {
+	Name    string
+	Type    ABIType
+	Indexed bool // only for event inputs
+}
+
+// ParseABIType parses a type of the JSON ABI. components are the fields of tuple types (tuple, tuple[], tuple[2] ...),
+// the synonyms uint, int and byte are resolved to uint256, int256 and bytes1.
+func ParseABIType(t string, components []ABIArgument) (*ABIType, error) {
+	if k := strings.LastIndex(t, "["); k >= 0 && strings.HasSuffix(t, "]") {
+		elem, err := ParseABIType(t[:k], components)
+		if err != nil {
+			return nil, err
+		}
+
+		length := t[k+1 : len(t)-1]
+		if length == "" {
+			return &ABIType{Kind: ABISlice, Elem: elem}, nil
+		}
+
+		n, err := strconv.Atoi(length)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid array length in abi type %v", t)
+		}
+
+		return &ABIType{Kind: ABIArray, Length: n, Elem: elem}, nil
+	}
+
+	switch t {
+	case BaseTypeAddress:
+		return &ABIType{Kind: ABIAddress, Size: 20}, nil
+	case BaseTypeBool:
+		return &ABIType{Kind: ABIBool}, nil
+	case BaseTypeString:
+		return &ABIType{Kind: ABIString}, nil
+	case BaseTypeBytes:
+		return &ABIType{Kind: ABIBytes}, nil
+	case BaseTypeFunction:
+		return &ABIType{Kind: ABIFunction, Size: 24}, nil
+	case BaseTypeUInt:
+		return &ABIType{Kind: ABIUInt, Size: 256}, nil
+	case BaseTypeInt:
+		return &ABIType{Kind: ABIInt, Size: 256}, nil
+	case "byte":
+		return &ABIType{Kind: ABIFixedBytes, Size: 1}, nil
+	case BaseTypeTuple:
+		if len(components) == 0 {
+			return nil, fmt.Errorf("tuple without components")
+		}
+		return &ABIType{Kind: ABITuple, Components: components}, nil
+	}
+
+	switch {
+	case strings.HasPrefix(t, BaseTypeUInt):
+		return parseSizedType(t, ABIUInt, t[len(BaseTypeUInt):])
+	case strings.HasPrefix(t, BaseTypeInt):
+		return parseSizedType(t, ABIInt, t[len(BaseTypeInt):])
+	case strings.HasPrefix(t, BaseTypeBytes):
+		return parseSizedType(t, ABIFixedBytes, t[len(BaseTypeBytes):])
+	}
+
+	return nil, fmt.Errorf("unsupported abi type %v", t)
+}
+
+// ParseABIMethodSignature parses a function signature as used for selectors, e.g. "transfer(address,uint256)" or
+// "submit((address,uint256)[],bytes)". Tuples are written as (T1,T2,...), the arguments and components are unnamed.
+func ParseABIMethodSignature(signature string) (*ABIMethod, error) {
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return nil, fmt.Errorf("invalid method signature %v", signature)
+	}
+
+	inputs, err := parseSignatureTypes(signature[open+1 : len(signature)-1])
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signature %v, %v", signature, err)
+	}
+
+	return &ABIMethod{Name: signature[:open], Type: "function", Inputs: inputs, StateMutability: "nonpayable"}, nil
+}
+
+// parseSignatureTypes parses a comma separated list of canonical types, e.g. "address,(uint256,bytes)[2]".
+func parseSignatureTypes(list string) ([]ABIArgument, error) {
+	args := make([]ABIArgument, 0)
+	if list == "" {
+		return args, nil
+	}
+
+	depth, start := 0, 0
+	for k := 0; k <= len(list); k++ {
+		if k < len(list) {
+			switch list[k] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in %v", list)
+			}
+			if list[k] != ',' || depth > 0 {
+				continue
+			}
+		} else if depth != 0 {
+			return nil, fmt.Errorf("unbalanced parentheses in %v", list)
+		}
+
+		t, err := parseSignatureType(list[start:k])
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, ABIArgument{Type: *t})
+		start = k + 1
+	}
+
+	return args, nil
+}
+
+func parseSignatureType(t string) (*ABIType, error) {
+	if !strings.HasPrefix(t, "(") {
+		return ParseABIType(t, nil)
+	}
+
+	closing := strings.LastIndex(t, ")")
+	components, err := parseSignatureTypes(t[1:closing])
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseABIType(BaseTypeTuple+t[closing+1:], components)
+}
+
+func parseSizedType(t string, kind ABIKind, size string) (*ABIType, error) {
+	n, err := strconv.Atoi(size)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported abi type %v", t)
+	}
+
+	if kind == ABIFixedBytes && (n < 1 || n > 32) || kind != ABIFixedBytes && (n < 8 || n > 256 || n%8 != 0) {
+		return nil, fmt.Errorf("invalid size of abi type %v", t)
+	}
+
+	return &ABIType{Kind: kind, Size: n}, nil
+}
+
+// String returns the canonical type used in signatures, tuples are written as (T1,T2,...).
+func (t *ABIType) String() string {
+	switch t.Kind {
+	case ABIUInt:
+		return BaseTypeUInt + strconv.Itoa(t.Size)
+	case ABIInt:
+		return BaseTypeInt + strconv.Itoa(t.Size)
+	case ABIAddress:
+		return BaseTypeAddress
+	case ABIBool:
+		return BaseTypeBool
+	case ABIFixedBytes:
+		return BaseTypeBytes + strconv.Itoa(t.Size)
+	case ABIBytes:
+		return BaseTypeBytes
+	case ABIString:
+		return BaseTypeString
+	case ABIFunction:
+		return BaseTypeFunction
+	case ABIArray:
+		return t.Elem.String() + "[" + strconv.Itoa(t.Length) + "]"
+	case ABISlice:
+		return t.Elem.String() + "[]"
+	case ABITuple:
+		return "(" + argumentTypes(t.Components) + ")"
+	}
+
+	return ""
+}
+
+// IsDynamic reports whether values of the type are encoded in the tail, with an offset in the head.
+func (t *ABIType) IsDynamic() bool {
+	switch t.Kind {
+	case ABIBytes, ABIString, ABISlice:
+		return true
+	case ABIArray:
+		return t.Elem.IsDynamic()
+	case ABITuple:
+		for _, c := range t.Components {
+			if c.Type.IsDynamic() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// headSize returns the number of bytes a value of the type occupies in the head of its enclosing tuple.
+func (t *ABIType) headSize() int {
+	if t.IsDynamic() {
+		return EthereumStandardByteLength
+	}
+
+	switch t.Kind {
+	case ABIArray:
+		return t.Length * t.Elem.headSize()
+	case ABITuple:
+		size := 0
+		for _, c := range t.Components {
+			size += c.Type.headSize()
+		}
+		return size
+	}
+
+	return EthereumStandardByteLength
+}
+
+func argumentTypes(args []ABIArgument) string {
+	types := make([]string, len(args))
+	for k, arg := range args {
+		types[k] = arg.Type.String()
+	}
+
+	return strings.Join(types, ",")
+}
+
+func argumentName(args []ABIArgument, k int) string {
+	if args[k].Name != "" {
+		return args[k].Name
+	}
+
+	return "arg" + strconv.Itoa(k)
+}
+
+// ABIMethod is a function, the constructor, the fallback or the receive function of a contract.
+type ABIMethod struct {
+	Name            string
+	Type            string // function, constructor, fallback or receive
+	Inputs          []ABIArgument
+	Outputs         []ABIArgument
+	StateMutability string // pure, view, nonpayable or payable
+}
+
+// Signature returns the canonical signature, e.g. transfer(address,uint256).
+func (m *ABIMethod) Signature() string {
+	return m.Name + "(" + argumentTypes(m.Inputs) + ")"
+}
+
+// Selector returns the first 4 bytes of the keccak256 of the signature, the prefix of the call data.
+func (m *ABIMethod) Selector() []byte {
+	return crypto.Keccak256([]byte(m.Signature()))[:4]
+}
+
+// IsConstant reports whether the method does not modify the state and can be used with eth_call.
+func (m *ABIMethod) IsConstant() bool {
+	return m.StateMutability == "view" || m.StateMutability == "pure"
+}
+
+// IsPayable reports whether the method accepts ether.
+func (m *ABIMethod) IsPayable() bool {
+	return m.StateMutability == "payable"
+}
+
+// ABIEvent is an event of a contract.
+type ABIEvent struct {
+	Name      string
+	Inputs    []ABIArgument
+	Anonymous bool // anonymous events do not store their ID in the first topic
+}
+
+// Signature returns the canonical signature, e.g. Transfer(address,address,uint256).
+func (e *ABIEvent) Signature() string {
+	return e.Name + "(" + argumentTypes(e.Inputs) + ")"
+}
+
+// ID returns the keccak256 of the signature, the first topic of the logs of the event.
+func (e *ABIEvent) ID() []byte {
+	return crypto.Keccak256([]byte(e.Signature()))
+}
+
+// ABIError is a custom error of a contract (solidity 0.8.4+), its revert data is encoded like a function call.
+type ABIError struct {
+	Name   string
+	Inputs []ABIArgument
+}
+
+// Signature returns the canonical signature, e.g. InsufficientBalance(uint256,uint256).
+func (e *ABIError) Signature() string {
+	return e.Name + "(" + argumentTypes(e.Inputs) + ")"
+}
+
+// Selector returns the first 4 bytes of the keccak256 of the signature, the prefix of the revert data.
+func (e *ABIError) Selector() []byte {
+	return crypto.Keccak256([]byte(e.Signature()))[:4]
+}
+
+// ABI is a parsed solidity JSON ABI.
+type ABI struct {
+	Constructor *ABIMethod
+	Fallback    *ABIMethod
+	Receive     *ABIMethod
+	Methods     []*ABIMethod
+	Events      []*ABIEvent
+	Errors      []*ABIError
+}
+
+type abiArgumentJSON struct {
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	Indexed    bool              `json:"indexed"`
+	Components []abiArgumentJSON `json:"components"`
+}
+
+type abiEntryJSON struct {
+	Type            string            `json:"type"`
+	Name            string            `json:"name"`
+	Inputs          []abiArgumentJSON `json:"inputs"`
+	Outputs         []abiArgumentJSON `json:"outputs"`
+	StateMutability string            `json:"stateMutability"`
+	Anonymous       bool              `json:"anonymous"`
+	Constant        bool              `json:"constant"` // before solidity 0.5: constant and payable instead of stateMutability
+	Payable         bool              `json:"payable"`
+}
+
+// ParseABI parses the JSON ABI of a contract as produced by solc, e.g. [{"type":"function","name":"balanceOf",...}, ...].
+func ParseABI(js []byte) (*ABI, error) {
+	var entries []abiEntryJSON
+	if err := json.Unmarshal(js, &entries); err != nil {
+		return nil, err
+	}
+
+	abi := new(ABI)
+
+	for _, entry := range entries {
+		inputs, err := toABIArguments(entry.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing inputs of %v, %v", entry.Name, err)
+		}
+
+		outputs, err := toABIArguments(entry.Outputs)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing outputs of %v, %v", entry.Name, err)
+		}
+
+		mutability := entry.StateMutability
+		if mutability == "" {
+			switch {
+			case entry.Constant:
+				mutability = "view"
+			case entry.Payable:
+				mutability = "payable"
+			default:
+				mutability = "nonpayable"
+			}
+		}
+
+		method := &ABIMethod{Name: entry.Name, Type: entry.Type, Inputs: inputs, Outputs: outputs, StateMutability: mutability}
+
+		switch entry.Type {
+		case "function", "":
+			method.Type = "function"
+			abi.Methods = append(abi.Methods, method)
+		case "constructor":
+			abi.Constructor = method
+		case "fallback":
+			abi.Fallback = method
+		case "receive":
+			abi.Receive = method
+		case "event":
+			abi.Events = append(abi.Events, &ABIEvent{Name: entry.Name, Inputs: inputs, Anonymous: entry.Anonymous})
+		case "error":
+			abi.Errors = append(abi.Errors, &ABIError{Name: entry.Name, Inputs: inputs})
+		default:
+			return nil, fmt.Errorf("unknown abi entry type %v", entry.Type)
+		}
+	}
+
+	return abi, nil
+}
+
+func toABIArguments(list []abiArgumentJSON) ([]ABIArgument, error) {
+	args := make([]ABIArgument, len(list))
+
+	for k, v := range list {
+		components, err := toABIArguments(v.Components)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := ParseABIType(v.Type, components)
+		if err != nil {
+			return nil, err
+		}
+
+		args[k] = ABIArgument{Name: v.Name, Type: *t, Indexed: v.Indexed}
+	}
+
+	return args, nil
+}
+
+// Method returns the function with the given name or, for overloaded functions, the given signature (e.g. "safeTransferFrom(address,address,uint256)").
+func (abi *ABI) Method(name string) (*ABIMethod, error) {
+	var found *ABIMethod
+
+	for _, m := range abi.Methods {
+		if m.Signature() == name {
+			return m, nil
+		}
+		if m.Name == name {
+			if found != nil {
+				return nil, fmt.Errorf("method %v is overloaded, use its signature", name)
+			}
+			found = m
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("method %v not found", name)
+	}
+
+	return found, nil
+}
+
+// MethodBySelector returns the function whose selector is the first 4 bytes of data, e.g. of a transaction input.
+func (abi *ABI) MethodBySelector(data []byte) (*ABIMethod, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("selector needs 4 bytes, got %v", len(data))
+	}
+
+	for _, m := range abi.Methods {
+		if bytes.Equal(m.Selector(), data[:4]) {
+			return m, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no method with selector %x", data[:4])
+}
+
+// Event returns the event with the given name or signature.
+func (abi *ABI) Event(name string) (*ABIEvent, error) {
+	var found *ABIEvent
+
+	for _, e := range abi.Events {
+		if e.Signature() == name {
+			return e, nil
+		}
+		if e.Name == name {
+			if found != nil {
+				return nil, fmt.Errorf("event %v is overloaded, use its signature", name)
+			}
+			found = e
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("event %v not found", name)
+	}
+
+	return found, nil
+}
+
+// EventByID returns the (not anonymous) event whose ID is topic, the first topic of its logs.
+func (abi *ABI) EventByID(topic []byte) (*ABIEvent, error) {
+	for _, e := range abi.Events {
+		if !e.Anonymous && bytes.Equal(e.ID(), topic) {
+			return e, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no event with id %x", topic)
+}
+
+// ErrorBySelector returns the custom error whose selector is the first 4 bytes of the revert data.
+func (abi *ABI) ErrorBySelector(data []byte) (*ABIError, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("selector needs 4 bytes, got %v", len(data))
+	}
+
+	for _, e := range abi.Errors {
+		if bytes.Equal(e.Selector(), data[:4]) {
+			return e, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no error with selector %x", data[:4])
+}
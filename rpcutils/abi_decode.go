@@ -0,0 +1,242 @@
+package rpcutils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+)
+
+// DecodeABIArguments decodes the values of args from data, e.g. the result of eth_call or the data of a log.
+//
+// uint<M> and int<M> are decoded to *big.Int, address to *rpctypes.EtherAddress, bool to bool, string to string,
+// bytes, bytes<M> and function to []byte, arrays and tuples to []interface{}.
+func DecodeABIArguments(args []ABIArgument, data []byte) ([]interface{}, error) {
+	types := make([]*ABIType, len(args))
+	for k := range args {
+		types[k] = &args[k].Type
+	}
+
+	return decodeABISequence(types, data)
+}
+
+// Unpack decodes the return values of the method from the result of eth_call.
+func (m *ABIMethod) Unpack(data []byte) ([]interface{}, error) {
+	return DecodeABIArguments(m.Outputs, data)
+}
+
+// UnpackInput decodes the arguments of the method from call data, e.g. the input of a transaction.
+func (m *ABIMethod) UnpackInput(data []byte) ([]interface{}, error) {
+	if len(data) < 4 {
+		return nil, errors.New("call data shorter than a selector")
+	}
+
+	return DecodeABIArguments(m.Inputs, data[4:])
+}
+
+// Unpack decodes the arguments of the error from revert data, including the selector.
+func (e *ABIError) Unpack(data []byte) ([]interface{}, error) {
+	if len(data) < 4 {
+		return nil, errors.New("revert data shorter than a selector")
+	}
+
+	return DecodeABIArguments(e.Inputs, data[4:])
+}
+
+// DecodeLog decodes the inputs of the event from the topics and data of the log, in the order of the inputs.
+// Indexed inputs of dynamic types (string, bytes, arrays, tuples) are only stored as keccak256 hash, their value is the 32 byte hash.
+func (e *ABIEvent) DecodeLog(log *rpctypes.EtherLog) ([]interface{}, error) {
+	topics := log.Topics
+	if !e.Anonymous {
+		if len(topics) == 0 || !bytes.Equal(padLeft(topics[0].Bytes(), EthereumStandardByteLength), e.ID()) {
+			return nil, fmt.Errorf("log is no %v event", e.Name)
+		}
+		topics = topics[1:]
+	}
+
+	indexed := make([]ABIArgument, 0)
+	data := make([]ABIArgument, 0)
+	for _, input := range e.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		} else {
+			data = append(data, input)
+		}
+	}
+
+	if len(topics) != len(indexed) {
+		return nil, fmt.Errorf("log of %v has %v indexed topics, expected %v", e.Name, len(topics), len(indexed))
+	}
+
+	dataValues, err := DecodeABIArguments(data, log.Data.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, 0, len(e.Inputs))
+	for _, input := range e.Inputs {
+		if !input.Indexed {
+			values = append(values, dataValues[0])
+			dataValues = dataValues[1:]
+			continue
+		}
+
+		topic := padLeft(topics[0].Bytes(), EthereumStandardByteLength)
+		topics = topics[1:]
+
+		if input.Type.IsDynamic() || input.Type.Kind == ABIArray || input.Type.Kind == ABITuple {
+			values = append(values, topic)
+			continue
+		}
+
+		value, err := decodeABIValue(&input.Type, topic)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %v of %v, %v", input.Name, e.Name, err)
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// decodeABISequence decodes the values of a tuple or array whose encoding starts at data[0].
+// Offsets of dynamic values are relative to data[0].
+func decodeABISequence(types []*ABIType, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(types))
+	position := 0
+
+	for k, t := range types {
+		var err error
+
+		if t.IsDynamic() {
+			var offset int
+			if offset, err = readABIInt(data, position); err != nil {
+				return nil, err
+			}
+			values[k], err = decodeABIValue(t, data[offset:])
+		} else {
+			if position > len(data) {
+				return nil, errors.New("abi data too short")
+			}
+			values[k], err = decodeABIValue(t, data[position:])
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		position += t.headSize()
+	}
+
+	return values, nil
+}
+
+// decodeABIValue decodes a value of type t whose encoding starts at data[0].
+func decodeABIValue(t *ABIType, data []byte) (interface{}, error) {
+	switch t.Kind {
+	case ABIArray:
+		return decodeABISequence(repeatType(t.Elem, t.Length), data)
+	case ABISlice:
+		length, err := readABIInt(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		if length > len(data)/EthereumStandardByteLength {
+			return nil, fmt.Errorf("abi array length %v exceeds data", length)
+		}
+		return decodeABISequence(repeatType(t.Elem, length), data[EthereumStandardByteLength:])
+	case ABITuple:
+		types := make([]*ABIType, len(t.Components))
+		for k := range t.Components {
+			types[k] = &t.Components[k].Type
+		}
+		return decodeABISequence(types, data)
+	case ABIBytes, ABIString:
+		length, err := readABIInt(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		if EthereumStandardByteLength+length > len(data) {
+			return nil, fmt.Errorf("abi %v of %v bytes exceeds data", t.String(), length)
+		}
+		b := make([]byte, length)
+		copy(b, data[EthereumStandardByteLength:])
+		if t.Kind == ABIString {
+			return string(b), nil
+		}
+		return b, nil
+	}
+
+	if len(data) < EthereumStandardByteLength {
+		return nil, errors.New("abi data too short")
+	}
+	word := data[:EthereumStandardByteLength]
+
+	switch t.Kind {
+	case ABIUInt:
+		value := new(big.Int).SetBytes(word)
+		if value.BitLen() > t.Size {
+			return nil, fmt.Errorf("%x exceeds %v", word, t.String())
+		}
+		return value, nil
+	case ABIInt:
+		value := new(big.Int).SetBytes(word)
+		if word[0]&0x80 != 0 {
+			value.Sub(value, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		if value.Sign() >= 0 && value.BitLen() > t.Size-1 || value.Sign() < 0 && new(big.Int).Not(value).BitLen() > t.Size-1 {
+			return nil, fmt.Errorf("%x exceeds %v", word, t.String())
+		}
+		return value, nil
+	case ABIAddress:
+		return new(rpctypes.EtherAddress).FromBytes(word[EthereumStandardByteLength-rpctypes.EtherAddressLength:])
+	case ABIBool:
+		value := new(big.Int).SetBytes(word)
+		if value.BitLen() > 1 {
+			return nil, fmt.Errorf("%x is no bool", word)
+		}
+		return value.Sign() == 1, nil
+	case ABIFixedBytes, ABIFunction:
+		b := make([]byte, t.Size)
+		copy(b, word)
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("cannot decode abi type %v", t.String())
+}
+
+// readABIInt reads the offset or length at position, it must fit into data.
+func readABIInt(data []byte, position int) (int, error) {
+	if position < 0 || position+EthereumStandardByteLength > len(data) {
+		return 0, errors.New("abi data too short")
+	}
+
+	value := new(big.Int).SetBytes(data[position : position+EthereumStandardByteLength])
+	if !value.IsInt64() || value.Int64() > int64(len(data)) {
+		return 0, fmt.Errorf("abi offset or length %v exceeds data", value)
+	}
+
+	return int(value.Int64()), nil
+}
+
+func repeatType(t *ABIType, n int) []*ABIType {
+	types := make([]*ABIType, n)
+	for k := range types {
+		types[k] = t
+	}
+
+	return types
+}
+
+func padLeft(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+
+	return padded
+}
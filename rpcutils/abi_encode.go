@@ -0,0 +1,432 @@
+package rpcutils
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EncodeABIArguments encodes values as the arguments args, e.g. the call data of a function after its selector.
+// Static values are encoded in place, dynamic values (bytes, string, T[] and arrays or tuples containing them)
+// in the tail with their offset in the head.
+//
+// Integers can be given as *big.Int, *rpctypes.EtherValue or any go integer type, addresses as rpctypes.EtherAddress,
+// *rpctypes.EtherAddress, [20]byte or hex string, bytes and bytes<M> as []byte, [M]byte, rpctypes.HexString or hex string.
+// Arrays are given as slices or go arrays, tuples as []interface{} or structs whose fields are matched to the
+// components by name (case insensitive) or, for unnamed components, in their order.
+func EncodeABIArguments(args []ABIArgument, values []interface{}) ([]byte, error) {
+	if len(values) != len(args) {
+		return nil, fmt.Errorf("got %v values for %v arguments", len(values), len(args))
+	}
+
+	types := make([]*ABIType, len(args))
+	for k := range args {
+		types[k] = &args[k].Type
+	}
+
+	return encodeABISequence(types, values, func(k int) string { return argumentName(args, k) })
+}
+
+// Pack returns the call data of the method: its selector followed by the encoded arguments. For the constructor,
+// it returns only the encoded arguments, which are appended to the bytecode of the contract.
+func (m *ABIMethod) Pack(values ...interface{}) ([]byte, error) {
+	encoded, err := EncodeABIArguments(m.Inputs, values)
+	if err != nil {
+		return nil, fmt.Errorf("error packing %v, %v", m.Signature(), err)
+	}
+
+	if m.Type == "constructor" {
+		return encoded, nil
+	}
+
+	return append(m.Selector(), encoded...), nil
+}
+
+// MaxEventTopics is the number of topics of a log, the ID of the event and 3 indexed inputs or 4 for anonymous events.
+const MaxEventTopics = 4
+
+// Topics returns the topics of a log filter for the event. query holds the accepted values of the indexed inputs
+// in their order, a nil or empty entry matches any value. Values of indexed string and bytes inputs are hashed.
+func (e *ABIEvent) Topics(query ...[]interface{}) ([][]string, error) {
+	topics := make([][]string, 0, MaxEventTopics)
+	if !e.Anonymous {
+		topics = append(topics, []string{rpctypes.NewHexStringFromBytes(e.ID()).Hash()})
+	}
+
+	indexed := make([]ABIArgument, 0)
+	for _, input := range e.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+
+	if len(query) > len(indexed) {
+		return nil, fmt.Errorf("event %v has %v indexed inputs, got %v", e.Name, len(indexed), len(query))
+	}
+
+	for k, values := range query {
+		t := &indexed[k].Type
+		topic := make([]string, len(values))
+
+		for i, value := range values {
+			var word []byte
+			var err error
+
+			switch {
+			case t.Kind == ABIString:
+				s, ok := value.(string)
+				if !ok {
+					return nil, fmt.Errorf("%v (%T) is no string", value, value)
+				}
+				word = crypto.Keccak256([]byte(s))
+			case t.Kind == ABIBytes:
+				var b []byte
+				if b, err = abiBytes(value); err == nil {
+					word = crypto.Keccak256(b)
+				}
+			default:
+				word, err = encodeABIWord(t, value)
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("error encoding topic %v, %v", argumentName(indexed, k), err)
+			}
+			topic[i] = rpctypes.NewHexStringFromBytes(word).Hash()
+		}
+
+		topics = append(topics, topic)
+	}
+
+	return topics, nil
+}
+
+// encodeABISequence encodes the values of a tuple or array, the heads of all values followed by the tails of the dynamic ones.
+// Offsets are relative to the start of the sequence.
+func encodeABISequence(types []*ABIType, values []interface{}, name func(k int) string) ([]byte, error) {
+	headSize := 0
+	for _, t := range types {
+		headSize += t.headSize()
+	}
+
+	head := make([]byte, 0, headSize)
+	tail := make([]byte, 0)
+
+	for k, t := range types {
+		encoded, err := encodeABIValue(t, values[k])
+		if err != nil {
+			return nil, fmt.Errorf("error encoding %v, %v", name(k), err)
+		}
+
+		if !t.IsDynamic() {
+			head = append(head, encoded...)
+			continue
+		}
+
+		head = append(head, encodeABISize(headSize+len(tail))...)
+		tail = append(tail, encoded...)
+	}
+
+	return append(head, tail...), nil
+}
+
+// encodeABIValue encodes value as type t. Dynamic values are encoded as they appear in the tail, without their offset.
+func encodeABIValue(t *ABIType, value interface{}) ([]byte, error) {
+	switch t.Kind {
+	case ABIArray:
+		elems, err := abiElements(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(elems) != t.Length {
+			return nil, fmt.Errorf("got %v elements for %v", len(elems), t.String())
+		}
+		return encodeABISequence(repeatType(t.Elem, len(elems)), elems, elementName)
+	case ABISlice:
+		elems, err := abiElements(value)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := encodeABISequence(repeatType(t.Elem, len(elems)), elems, elementName)
+		if err != nil {
+			return nil, err
+		}
+		return append(encodeABISize(len(elems)), encoded...), nil
+	case ABITuple:
+		fields, err := abiTupleFields(t, value)
+		if err != nil {
+			return nil, err
+		}
+		types := make([]*ABIType, len(t.Components))
+		for k := range t.Components {
+			types[k] = &t.Components[k].Type
+		}
+		return encodeABISequence(types, fields, func(k int) string { return argumentName(t.Components, k) })
+	case ABIString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%v (%T) is no string", value, value)
+		}
+		return encodeABIBytes([]byte(s)), nil
+	case ABIBytes:
+		b, err := abiBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return encodeABIBytes(b), nil
+	}
+
+	return encodeABIWord(t, value)
+}
+
+// encodeABIBytes encodes bytes and string: the length followed by the content, right padded to a multiple of 32 bytes.
+func encodeABIBytes(b []byte) []byte {
+	words := (len(b) + EthereumStandardByteLength - 1) / EthereumStandardByteLength
+	encoded := make([]byte, EthereumStandardByteLength*(1+words))
+	copy(encoded, encodeABISize(len(b)))
+	copy(encoded[EthereumStandardByteLength:], b)
+
+	return encoded
+}
+
+// encodeABISize encodes an offset or length.
+func encodeABISize(n int) []byte {
+	return padLeft(new(big.Int).SetInt64(int64(n)).Bytes(), EthereumStandardByteLength)
+}
+
+func elementName(k int) string {
+	return "element " + strconv.Itoa(k)
+}
+
+// encodeABIWord encodes a value type into one 32 byte word.
+func encodeABIWord(t *ABIType, value interface{}) ([]byte, error) {
+	switch t.Kind {
+	case ABIUInt, ABIInt:
+		i, err := abiBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		return encodeABIInt(t, i)
+	case ABIAddress:
+		address, err := abiAddress(value)
+		if err != nil {
+			return nil, err
+		}
+		return padLeft(address.Bytes(), EthereumStandardByteLength), nil
+	case ABIBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%v is no bool", value)
+		}
+		if b {
+			return padLeft([]byte{1}, EthereumStandardByteLength), nil
+		}
+		return make([]byte, EthereumStandardByteLength), nil
+	case ABIFixedBytes, ABIFunction:
+		b, err := abiBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > t.Size {
+			return nil, fmt.Errorf("%v bytes do not fit into %v", len(b), t.String())
+		}
+		word := make([]byte, EthereumStandardByteLength)
+		copy(word, b)
+		return word, nil
+	}
+
+	return nil, fmt.Errorf("abi encoding of %v is not supported", t.String())
+}
+
+// encodeABIInt encodes i as uint<M> or int<M>, negative values in two's complement.
+func encodeABIInt(t *ABIType, i *big.Int) ([]byte, error) {
+	if t.Kind == ABIUInt && (i.Sign() < 0 || i.BitLen() > t.Size) {
+		return nil, fmt.Errorf("%v does not fit into %v", i, t.String())
+	}
+
+	if t.Kind == ABIInt && (i.Sign() >= 0 && i.BitLen() > t.Size-1 || i.Sign() < 0 && new(big.Int).Not(i).BitLen() > t.Size-1) {
+		return nil, fmt.Errorf("%v does not fit into %v", i, t.String())
+	}
+
+	if i.Sign() < 0 {
+		i = new(big.Int).Add(i, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+
+	return padLeft(i.Bytes(), EthereumStandardByteLength), nil
+}
+
+func abiBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		if v == nil {
+			return nil, fmt.Errorf("integer cannot be nil")
+		}
+		return v, nil
+	case big.Int:
+		return &v, nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int8:
+		return big.NewInt(int64(v)), nil
+	case int16:
+		return big.NewInt(int64(v)), nil
+	case int32:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint8:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint16:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case *rpctypes.EtherValue:
+		return v.BigInt(), nil
+	}
+
+	// named integer types, e.g. type Amount uint64
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(v.Uint()), nil
+	}
+
+	return nil, fmt.Errorf("%v (%T) is no integer", value, value)
+}
+
+func abiAddress(value interface{}) (*rpctypes.EtherAddress, error) {
+	switch v := value.(type) {
+	case rpctypes.EtherAddress:
+		return &v, nil
+	case *rpctypes.EtherAddress:
+		if v == nil {
+			return nil, fmt.Errorf("address cannot be nil")
+		}
+		return v, nil
+	case string:
+		return new(rpctypes.EtherAddress).FromString(v)
+	}
+
+	if b, ok := byteArray(value); ok && len(b) == rpctypes.EtherAddressLength {
+		return new(rpctypes.EtherAddress).FromBytes(b)
+	}
+
+	return nil, fmt.Errorf("%v (%T) is no address", value, value)
+}
+
+func abiBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case rpctypes.HexString:
+		return v.Bytes(), nil
+	case *rpctypes.HexString:
+		return v.Bytes(), nil
+	case string:
+		hs, err := rpctypes.NewHexString(v)
+		if err != nil {
+			return nil, err
+		}
+		return hs.Bytes(), nil
+	}
+
+	if b, ok := byteArray(value); ok {
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("%v (%T) is no byte slice", value, value)
+}
+
+// byteArray returns the content of a go byte array, e.g. [32]byte or common.Hash.
+func byteArray(value interface{}) ([]byte, bool) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Array || v.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, false
+	}
+
+	b := make([]byte, v.Len())
+	reflect.Copy(reflect.ValueOf(b), v)
+
+	return b, true
+}
+
+// abiElements returns the elements of a slice or go array.
+func abiElements(value interface{}) ([]interface{}, error) {
+	if elems, ok := value.([]interface{}); ok {
+		return elems, nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("%v (%T) is no slice or array", value, value)
+	}
+
+	elems := make([]interface{}, v.Len())
+	for k := range elems {
+		elems[k] = v.Index(k).Interface()
+	}
+
+	return elems, nil
+}
+
+// abiTupleFields returns the values of the components of tuple t from a slice, a struct or a pointer to a struct.
+// Struct fields are matched to named components by name, ignoring case and underscores, otherwise the exported
+// fields are taken in their order.
+func abiTupleFields(t *ABIType, value interface{}) ([]interface{}, error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		fields, err := abiElements(value)
+		if err != nil {
+			return nil, fmt.Errorf("%v (%T) is no tuple", value, value)
+		}
+		if len(fields) != len(t.Components) {
+			return nil, fmt.Errorf("got %v values for %v", len(fields), t.String())
+		}
+		return fields, nil
+	}
+
+	exported := make([]reflect.Value, 0, v.NumField())
+	byName := make(map[string]reflect.Value, v.NumField())
+	for k := 0; k < v.NumField(); k++ {
+		if f := v.Type().Field(k); f.PkgPath == "" {
+			exported = append(exported, v.Field(k))
+			byName[normalizeFieldName(f.Name)] = v.Field(k)
+		}
+	}
+
+	fields := make([]interface{}, len(t.Components))
+	for k, c := range t.Components {
+		if c.Name == "" {
+			if k >= len(exported) {
+				return nil, fmt.Errorf("%v has no field for %v", v.Type(), argumentName(t.Components, k))
+			}
+			fields[k] = exported[k].Interface()
+			continue
+		}
+
+		f, ok := byName[normalizeFieldName(c.Name)]
+		if !ok {
+			return nil, fmt.Errorf("%v has no field %v", v.Type(), c.Name)
+		}
+		fields[k] = f.Interface()
+	}
+
+	return fields, nil
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.Replace(name, "_", "", -1))
+}
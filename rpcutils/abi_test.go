@@ -0,0 +1,388 @@
+package rpcutils
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testABI = `[
+	{"type":"constructor","inputs":[{"name":"supply","type":"uint256"}],"stateMutability":"nonpayable"},
+	{"type":"fallback","stateMutability":"payable"},
+	{"type":"receive","stateMutability":"payable"},
+	{"type":"function","name":"balanceOf","inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint256"}],"stateMutability":"view"},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable"},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"}],"outputs":[]},
+	{"type":"function","name":"safeTransferFrom","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"data","type":"bytes"}],"outputs":[]},
+	{"type":"function","name":"orders","inputs":[],"outputs":[{"name":"","type":"tuple[]","components":[{"name":"maker","type":"address"},{"name":"amounts","type":"uint128[2]"},{"name":"note","type":"string"}]}],"constant":true},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}],"anonymous":false},
+	{"type":"event","name":"Named","inputs":[{"name":"name","type":"string","indexed":true},{"name":"delta","type":"int64","indexed":false}],"anonymous":false},
+	{"type":"error","name":"InsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]}
+]`
+
+func mustParseTestABI(t *testing.T) *ABI {
+	abi, err := ParseABI([]byte(testABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return abi
+}
+
+func TestParseABI(t *testing.T) {
+	abi := mustParseTestABI(t)
+
+	if abi.Constructor == nil || abi.Fallback == nil || abi.Receive == nil || len(abi.Methods) != 5 || len(abi.Events) != 2 || len(abi.Errors) != 1 {
+		t.Errorf("wrong abi entries [Expected: %v, Actual: %v]", "constructor, fallback, receive, 5 methods, 2 events, 1 error", abi)
+		return
+	}
+
+	tests := []struct {
+		signature string
+		selector  string
+	}{
+		{"balanceOf(address)", "70a08231"},
+		{"transfer(address,uint256)", "a9059cbb"},
+		{"safeTransferFrom(address,address,uint256)", "42842e0e"},
+		{"safeTransferFrom(address,address,uint256,bytes)", "b88d4fde"},
+		{"orders()", ""},
+	}
+
+	for _, test := range tests {
+		m, err := abi.Method(test.signature)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if m.Signature() != test.signature || test.selector != "" && hex.EncodeToString(m.Selector()) != test.selector {
+			t.Errorf("wrong method [Expected: %v %v, Actual: %v %x]", test.signature, test.selector, m.Signature(), m.Selector())
+		}
+	}
+
+	orders, _ := abi.Method("orders")
+	if orders.Outputs[0].Type.String() != "(address,uint128[2],string)[]" || !orders.IsConstant() {
+		t.Errorf("wrong tuple output [Expected: %v, Actual: %v]", "(address,uint128[2],string)[]", orders.Outputs[0].Type.String())
+	}
+
+	if _, err := abi.Method("safeTransferFrom"); err == nil {
+		t.Errorf("overloaded method found by name [Expected: %v, Actual: %v]", "error", err)
+	}
+
+	selector, _ := hex.DecodeString("a9059cbb0000")
+	if m, err := abi.MethodBySelector(selector); err != nil || m.Name != "transfer" {
+		t.Errorf("wrong method by selector [Expected: %v, Actual: %v %v]", "transfer", m, err)
+	}
+
+	transfer, _ := abi.Event("Transfer")
+	if id := hex.EncodeToString(transfer.ID()); id != "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef" {
+		t.Errorf("wrong event id [Expected: %v, Actual: %v]", "ddf252ad...", id)
+	}
+
+	if e, err := abi.EventByID(transfer.ID()); err != nil || e != transfer {
+		t.Errorf("wrong event by id [Expected: %v, Actual: %v %v]", transfer.Name, e, err)
+	}
+}
+
+func TestParseABIType(t *testing.T) {
+	valid := map[string]string{
+		"uint":         "uint256",
+		"int8":         "int8",
+		"byte":         "bytes1",
+		"bytes32":      "bytes32",
+		"address[]":    "address[]",
+		"uint256[2][]": "uint256[2][]",
+		"string[][3]":  "string[][3]",
+	}
+
+	for in, expected := range valid {
+		parsed, err := ParseABIType(in, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if parsed.String() != expected {
+			t.Errorf("wrong type [Expected: %v, Actual: %v]", expected, parsed.String())
+		}
+	}
+
+	for _, invalid := range []string{"uint7", "uint264", "bytes0", "bytes33", "fixed128x18", "address[0]", "tuple"} {
+		if _, err := ParseABIType(invalid, nil); err == nil {
+			t.Errorf("invalid type %v accepted [Expected: %v, Actual: %v]", invalid, "error", err)
+		}
+	}
+
+	dynamic, _ := ParseABIType("uint256[2][]", nil)
+	static, _ := ParseABIType("uint256[2][3]", nil)
+	if !dynamic.IsDynamic() || static.IsDynamic() || static.headSize() != 6*32 {
+		t.Errorf("wrong layout [Expected: %v, Actual: %v %v %v]", "dynamic, static of 192 bytes", dynamic.IsDynamic(), static.IsDynamic(), static.headSize())
+	}
+}
+
+func TestDecodeABIArguments(t *testing.T) {
+	abi := mustParseTestABI(t)
+	orders, _ := abi.Method("orders")
+
+	// one order (0x01..01, [1, 2], "hi")
+	data, _ := hex.DecodeString(strings.Join([]string{
+		"0000000000000000000000000000000000000000000000000000000000000020", // offset of the array
+		"0000000000000000000000000000000000000000000000000000000000000001", // length
+		"0000000000000000000000000000000000000000000000000000000000000020", // offset of the tuple
+		"0000000000000000000000000101010101010101010101010101010101010101", // maker
+		"0000000000000000000000000000000000000000000000000000000000000001", // amounts[0]
+		"0000000000000000000000000000000000000000000000000000000000000002", // amounts[1]
+		"0000000000000000000000000000000000000000000000000000000000000080", // offset of the note in the tuple
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"6869000000000000000000000000000000000000000000000000000000000000",
+	}, ""))
+
+	values, err := orders.Unpack(data)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	order := values[0].([]interface{})[0].([]interface{})
+	maker := order[0].(*rpctypes.EtherAddress)
+	amounts := order[1].([]interface{})
+
+	if maker.String() != "0x0101010101010101010101010101010101010101" || amounts[1].(*big.Int).Int64() != 2 || order[2].(string) != "hi" {
+		t.Errorf("wrong order [Expected: %v, Actual: %v]", "0x0101...01 [1 2] hi", order)
+	}
+
+	if _, err := orders.Unpack(data[:len(data)-32]); err == nil {
+		t.Errorf("truncated data accepted [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestABIMethod_Pack(t *testing.T) {
+	abi := mustParseTestABI(t)
+	transfer, _ := abi.Method("transfer")
+
+	data, err := transfer.Pack("0x7f0d15c7faae65896648c8273b6d7e43f58fa842", big.NewInt(1000))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := "a9059cbb" +
+		"0000000000000000000000007f0d15c7faae65896648c8273b6d7e43f58fa842" +
+		"00000000000000000000000000000000000000000000000000000000000003e8"
+	if hex.EncodeToString(data) != expected {
+		t.Errorf("wrong call data [Expected: %v, Actual: %x]", expected, data)
+	}
+
+	if _, err := transfer.Pack("0x7f0d15c7faae65896648c8273b6d7e43f58fa842", big.NewInt(-1)); err == nil {
+		t.Errorf("negative uint accepted [Expected: %v, Actual: %v]", "error", err)
+	}
+
+	if _, err := transfer.Pack("0x7f0d15c7faae65896648c8273b6d7e43f58fa842"); err == nil {
+		t.Errorf("missing argument accepted [Expected: %v, Actual: %v]", "error", err)
+	}
+
+	constructor, _ := abi.Constructor.Pack(1)
+	if len(constructor) != 32 {
+		t.Errorf("constructor arguments with selector [Expected: %v, Actual: %v]", 32, len(constructor))
+	}
+}
+
+func TestABIEvent_DecodeLog(t *testing.T) {
+	abi := mustParseTestABI(t)
+	named, _ := abi.Event("Named")
+
+	topics, err := named.Topics([]interface{}{"alice", "bob"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(topics) != 2 || len(topics[1]) != 2 || topics[1][0] != "0x"+hex.EncodeToString(crypto.Keccak256([]byte("alice"))) {
+		t.Errorf("wrong topics [Expected: %v, Actual: %v]", "id and keccak of alice and bob", topics)
+		return
+	}
+
+	id, _ := rpctypes.NewHexString(topics[0][0])
+	name, _ := rpctypes.NewHexString(topics[1][0])
+	data, _ := rpctypes.NewHexString("0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe")
+
+	values, err := named.DecodeLog(&rpctypes.EtherLog{Topics: []rpctypes.HexString{*id, *name}, Data: *data})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if values[1].(*big.Int).Int64() != -2 || hex.EncodeToString(values[0].([]byte)) != name.Plain() {
+		t.Errorf("wrong values [Expected: %v, Actual: %v]", "keccak of alice and -2", values)
+	}
+
+	transfer, _ := abi.Event("Transfer")
+	if _, err := transfer.DecodeLog(&rpctypes.EtherLog{Topics: []rpctypes.HexString{*id, *name}, Data: *data}); err == nil {
+		t.Errorf("log of other event decoded [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestEncodeABIArguments_SpecExamples(t *testing.T) {
+	// the examples of the solidity ABI specification
+	tests := []struct {
+		signature string
+		values    []interface{}
+		expected  []string
+	}{
+		{
+			"sam(bytes,bool,uint256[])",
+			[]interface{}{[]byte("dave"), true, []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}},
+			[]string{
+				"a5643bf2",
+				"0000000000000000000000000000000000000000000000000000000000000060",
+				"0000000000000000000000000000000000000000000000000000000000000001",
+				"00000000000000000000000000000000000000000000000000000000000000a0",
+				"0000000000000000000000000000000000000000000000000000000000000004",
+				"6461766500000000000000000000000000000000000000000000000000000000",
+				"0000000000000000000000000000000000000000000000000000000000000003",
+				"0000000000000000000000000000000000000000000000000000000000000001",
+				"0000000000000000000000000000000000000000000000000000000000000002",
+				"0000000000000000000000000000000000000000000000000000000000000003",
+			},
+		},
+		{
+			"f(uint256,uint32[],bytes10,bytes)",
+			[]interface{}{0x123, []uint32{0x456, 0x789}, []byte("1234567890"), []byte("Hello, world!")},
+			[]string{
+				"8be65246",
+				"0000000000000000000000000000000000000000000000000000000000000123",
+				"0000000000000000000000000000000000000000000000000000000000000080",
+				"3132333435363738393000000000000000000000000000000000000000000000",
+				"00000000000000000000000000000000000000000000000000000000000000e0",
+				"0000000000000000000000000000000000000000000000000000000000000002",
+				"0000000000000000000000000000000000000000000000000000000000000456",
+				"0000000000000000000000000000000000000000000000000000000000000789",
+				"000000000000000000000000000000000000000000000000000000000000000d",
+				"48656c6c6f2c20776f726c642100000000000000000000000000000000000000",
+			},
+		},
+		{
+			"g(uint256[][],string[])",
+			[]interface{}{[][]int{{1, 2}, {3}}, []string{"one", "two", "three"}},
+			[]string{
+				"2289b18c",
+				"0000000000000000000000000000000000000000000000000000000000000040",
+				"0000000000000000000000000000000000000000000000000000000000000140",
+				"0000000000000000000000000000000000000000000000000000000000000002",
+				"0000000000000000000000000000000000000000000000000000000000000040",
+				"00000000000000000000000000000000000000000000000000000000000000a0",
+				"0000000000000000000000000000000000000000000000000000000000000002",
+				"0000000000000000000000000000000000000000000000000000000000000001",
+				"0000000000000000000000000000000000000000000000000000000000000002",
+				"0000000000000000000000000000000000000000000000000000000000000001",
+				"0000000000000000000000000000000000000000000000000000000000000003",
+				"0000000000000000000000000000000000000000000000000000000000000003",
+				"0000000000000000000000000000000000000000000000000000000000000060",
+				"00000000000000000000000000000000000000000000000000000000000000a0",
+				"00000000000000000000000000000000000000000000000000000000000000e0",
+				"0000000000000000000000000000000000000000000000000000000000000003",
+				"6f6e650000000000000000000000000000000000000000000000000000000000",
+				"0000000000000000000000000000000000000000000000000000000000000003",
+				"74776f0000000000000000000000000000000000000000000000000000000000",
+				"0000000000000000000000000000000000000000000000000000000000000005",
+				"7468726565000000000000000000000000000000000000000000000000000000",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		m, err := ParseABIMethodSignature(test.signature)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		data, err := m.Pack(test.values...)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		expected := strings.Join(test.expected, "")
+		if hex.EncodeToString(data) != expected {
+			t.Errorf("wrong call data of %v [Expected: %v, Actual: %x]", test.signature, expected, data)
+		}
+	}
+}
+
+func TestEncodeABIArguments_Tuples(t *testing.T) {
+	type amount int64
+	type order struct {
+		Maker   rpctypes.EtherAddress
+		Amounts [2]amount
+		Note    string
+		ignored int
+	}
+
+	abi := mustParseTestABI(t)
+	orders, _ := abi.Method("orders")
+	maker, _ := new(rpctypes.EtherAddress).FromString("0x0101010101010101010101010101010101010101")
+
+	// the struct and the []interface{} are encoded like the result of orders() decoded in TestDecodeABIArguments
+	for _, value := range []interface{}{
+		[]order{{Maker: *maker, Amounts: [2]amount{1, 2}, Note: "hi"}},
+		[]interface{}{[]interface{}{maker, []int{1, 2}, "hi"}},
+	} {
+		data, err := EncodeABIArguments(orders.Outputs, []interface{}{value})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		values, err := orders.Unpack(data)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		decoded := values[0].([]interface{})[0].([]interface{})
+		if decoded[0].(*rpctypes.EtherAddress).String() != maker.String() || decoded[1].([]interface{})[1].(*big.Int).Int64() != 2 || decoded[2] != "hi" {
+			t.Errorf("wrong round trip [Expected: %v, Actual: %v]", "0x0101...01 [1 2] hi", decoded)
+		}
+	}
+
+	submit, err := ParseABIMethodSignature("submit((address,int8)[2],bytes32)")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if submit.Signature() != "submit((address,int8)[2],bytes32)" {
+		t.Errorf("wrong signature [Expected: %v, Actual: %v]", "submit((address,int8)[2],bytes32)", submit.Signature())
+	}
+
+	data, err := submit.Pack([][]interface{}{{maker, -1}, {maker, 127}}, [32]byte{0xff})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// static tuples and arrays are encoded in place
+	if len(data) != 4+5*32 || hex.EncodeToString(data[4+32:4+64]) != strings.Repeat("ff", 32) || data[4+4*32] != 0xff {
+		t.Errorf("wrong call data [Expected: %v, Actual: %x]", "4 static tuple words and bytes32", data)
+	}
+
+	invalid := [][]interface{}{
+		{[][]interface{}{{maker, 128}, {maker, 0}}, [32]byte{}},
+		{[][]interface{}{{maker, 0}}, [32]byte{}},
+		{[][]interface{}{{maker}, {maker}}, [32]byte{}},
+	}
+	for _, values := range invalid {
+		if _, err := submit.Pack(values...); err == nil {
+			t.Errorf("invalid values %v accepted [Expected: %v, Actual: %v]", values, "error", err)
+		}
+	}
+
+	for _, signature := range []string{"f(uint256", "f((uint256)", "f(uint256))", "(uint256)"} {
+		if _, err := ParseABIMethodSignature(signature); err == nil {
+			t.Errorf("invalid signature %v accepted [Expected: %v, Actual: %v]", signature, "error", err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package rpcutils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// EncodeRLP encodes a value with the recursive length prefix encoding of the yellow paper, appendix B.
+//
+// Supported values are []byte and string (byte strings), *big.Int, uint64, uint and int64 (non negative integers
+// without leading zeros, zero is the empty string) and []interface{} (lists of supported values).
+func EncodeRLP(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return encodeRLPString(v), nil
+	case string:
+		return encodeRLPString([]byte(v)), nil
+	case *big.Int:
+		if v == nil {
+			return encodeRLPString(nil), nil
+		}
+		if v.Sign() < 0 {
+			return nil, fmt.Errorf("rlp: cannot encode negative integer %v", v)
+		}
+		return encodeRLPString(v.Bytes()), nil
+	case uint64:
+		return encodeRLPString(new(big.Int).SetUint64(v).Bytes()), nil
+	case uint:
+		return encodeRLPString(new(big.Int).SetUint64(uint64(v)).Bytes()), nil
+	case int64:
+		if v < 0 {
+			return nil, fmt.Errorf("rlp: cannot encode negative integer %v", v)
+		}
+		return encodeRLPString(big.NewInt(v).Bytes()), nil
+	case []interface{}:
+		payload := make([]byte, 0)
+		for _, item := range v {
+			encoded, err := EncodeRLP(item)
+			if err != nil {
+				return nil, err
+			}
+			payload = append(payload, encoded...)
+		}
+		return append(rlpHeader(0xc0, len(payload)), payload...), nil
+	}
+
+	return nil, fmt.Errorf("rlp: unsupported type %T", value)
+}
+
+func encodeRLPString(b []byte) []byte {
+	if len(b) == 1 && b[0] < 0x80 {
+		return []byte{b[0]}
+	}
+
+	return append(rlpHeader(0x80, len(b)), b...)
+}
+
+// rlpHeader returns the prefix of a string (offset 0x80) or list (offset 0xc0) payload of the given length.
+func rlpHeader(offset byte, length int) []byte {
+	if length < 56 {
+		return []byte{offset + byte(length)}
+	}
+
+	size := big.NewInt(int64(length)).Bytes()
+	return append([]byte{offset + 55 + byte(len(size))}, size...)
+}
+
+// DecodeRLP decodes a single rlp item which must span all of data. Byte strings are returned as []byte,
+// lists as []interface{} of decoded items.
+func DecodeRLP(data []byte) (interface{}, error) {
+	item, rest, err := decodeRLPItem(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("rlp: %v trailing bytes after item", len(rest))
+	}
+
+	return item, nil
+}
+
+func decodeRLPItem(data []byte) (interface{}, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, errors.New("rlp: unexpected end of input")
+	}
+
+	prefix := data[0]
+
+	switch {
+	case prefix < 0x80:
+		return data[:1], data[1:], nil
+	case prefix < 0xc0:
+		payload, rest, err := rlpPayload(data, 0x80)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(payload) == 1 && payload[0] < 0x80 {
+			return nil, nil, errors.New("rlp: single byte below 0x80 must not have a string header")
+		}
+		return payload, rest, nil
+	default:
+		payload, rest, err := rlpPayload(data, 0xc0)
+		if err != nil {
+			return nil, nil, err
+		}
+		list := make([]interface{}, 0)
+		for len(payload) > 0 {
+			var item interface{}
+			item, payload, err = decodeRLPItem(payload)
+			if err != nil {
+				return nil, nil, err
+			}
+			list = append(list, item)
+		}
+		return list, rest, nil
+	}
+}
+
+// rlpPayload splits the string (offset 0x80) or list (offset 0xc0) starting at data into its payload and the rest.
+func rlpPayload(data []byte, offset byte) ([]byte, []byte, error) {
+	length := int(data[0] - offset)
+	start := 1
+
+	if length > 55 {
+		size := length - 55
+		if len(data) < 1+size {
+			return nil, nil, errors.New("rlp: unexpected end of input")
+		}
+		if data[1] == 0 {
+			return nil, nil, errors.New("rlp: length with leading zero")
+		}
+		if size > 4 {
+			return nil, nil, fmt.Errorf("rlp: length of %v bytes is too big", size)
+		}
+		length = int(new(big.Int).SetBytes(data[1 : 1+size]).Int64())
+		if length < 56 {
+			return nil, nil, errors.New("rlp: long header for short payload")
+		}
+		start += size
+	}
+
+	if len(data) < start+length {
+		return nil, nil, errors.New("rlp: unexpected end of input")
+	}
+
+	return data[start : start+length], data[start+length:], nil
+}
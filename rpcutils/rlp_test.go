@@ -0,0 +1,82 @@
+package rpcutils
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestEncodeRLP(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{"dog", "83646f67"},
+		{[]interface{}{"cat", "dog"}, "c88363617483646f67"},
+		{"", "80"},
+		{[]interface{}{}, "c0"},
+		{uint64(0), "80"},
+		{[]byte{0x00}, "00"},
+		{uint64(15), "0f"},
+		{uint64(1024), "820400"},
+		{[]interface{}{[]interface{}{}, []interface{}{[]interface{}{}}, []interface{}{[]interface{}{}, []interface{}{[]interface{}{}}}}, "c7c0c1c0c3c0c1c0"},
+		{"Lorem ipsum dolor sit amet, consectetur adipisicing elit", "b838" + hex.EncodeToString([]byte("Lorem ipsum dolor sit amet, consectetur adipisicing elit"))},
+		{new(big.Int).Lsh(big.NewInt(1), 64), "89010000000000000000"},
+	}
+
+	for _, test := range tests {
+		encoded, err := EncodeRLP(test.value)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if actual := hex.EncodeToString(encoded); actual != test.expected {
+			t.Errorf("%v [Expected: %v, Actual: %v]", test.value, test.expected, actual)
+		}
+	}
+
+	if _, err := EncodeRLP(big.NewInt(-1)); err == nil || !strings.Contains(err.Error(), "negative") {
+		t.Errorf("negative integer encoded [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestDecodeRLP(t *testing.T) {
+	values := []interface{}{
+		[]byte("dog"),
+		[]interface{}{[]byte("cat"), []byte("dog")},
+		[]byte{},
+		[]interface{}{},
+		[]byte{0x0f},
+		[]byte{0x04, 0x00},
+		[]interface{}{[]interface{}{}, []interface{}{[]interface{}{}}, []interface{}{[]interface{}{}, []interface{}{[]interface{}{}}}},
+		[]byte("Lorem ipsum dolor sit amet, consectetur adipisicing elit"),
+	}
+
+	for _, value := range values {
+		encoded, err := EncodeRLP(value)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		decoded, err := DecodeRLP(encoded)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		if expected, actual := fmt.Sprintf("%v", value), fmt.Sprintf("%v", decoded); expected != actual {
+			t.Errorf("wrong decoded value [Expected: %v, Actual: %v]", expected, actual)
+		}
+	}
+
+	for _, invalid := range []string{"", "83646f", "8100", "c883636174", "83646f6700", "b80100"} {
+		data, _ := hex.DecodeString(invalid)
+		if _, err := DecodeRLP(data); err == nil {
+			t.Errorf("invalid rlp %v decoded [Expected: %v, Actual: %v]", invalid, "error", nil)
+		}
+	}
+}
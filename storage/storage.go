@@ -0,0 +1,199 @@
+// Package storage computes the storage slots of solidity state variables for eth_getStorageAt
+// and decodes the returned words.
+//
+// State variables are laid out from slot 0 in declaration order. Value types smaller than 32 bytes share a slot
+// with their neighbours, starting at its low-order end (see Packed). Mappings and dynamic arrays occupy one slot
+// themselves, their elements live at keccak256 derived slots (see MappingSlot and ArrayElement).
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const WordSize = 32
+
+var (
+	// EIP-1967 proxy slots: keccak256 of the label minus 1
+	ImplementationSlot = eip1967Slot("eip1967.proxy.implementation")
+	AdminSlot          = eip1967Slot("eip1967.proxy.admin")
+	BeaconSlot         = eip1967Slot("eip1967.proxy.beacon")
+
+	maxSlot = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+func eip1967Slot(label string) *big.Int {
+	slot := new(big.Int).SetBytes(crypto.Keccak256([]byte(label)))
+	return slot.Sub(slot, big.NewInt(1))
+}
+
+// SlotBytes returns the slot as 32 byte big endian word.
+func SlotBytes(slot *big.Int) []byte {
+	return PadWord(slot.Bytes())
+}
+
+// PadWord left pads b with zeros to 32 bytes, the in-memory encoding of value types.
+func PadWord(b []byte) []byte {
+	if len(b) >= WordSize {
+		return b[len(b)-WordSize:]
+	}
+
+	word := make([]byte, WordSize)
+	copy(word[WordSize-len(b):], b)
+
+	return word
+}
+
+// AddressKey encodes an address as mapping key.
+func AddressKey(address *rpctypes.EtherAddress) []byte {
+	return PadWord(address.Bytes())
+}
+
+// UintKey encodes an unsigned integer as mapping key.
+func UintKey(key *big.Int) []byte {
+	return PadWord(key.Bytes())
+}
+
+// BoolKey encodes a bool as mapping key.
+func BoolKey(key bool) []byte {
+	if key {
+		return PadWord([]byte{1})
+	}
+
+	return PadWord(nil)
+}
+
+// StringKey encodes a string as mapping key. string and bytes keys are not padded.
+func StringKey(key string) []byte {
+	return []byte(key)
+}
+
+// MappingSlot returns the slot of the value stored under key in the mapping at slot: keccak256(key . slot).
+// Keys of value types must be padded to 32 bytes (AddressKey, UintKey, BoolKey, PadWord for bytesN left aligned).
+// For nested mappings, pass the result as slot of the next level.
+func MappingSlot(slot *big.Int, key []byte) *big.Int {
+	return new(big.Int).SetBytes(crypto.Keccak256(key, SlotBytes(slot)))
+}
+
+// ArrayDataSlot returns the slot of the first element of the dynamic array (or the data of a long bytes/string) at slot.
+// The slot itself holds the length of the array.
+func ArrayDataSlot(slot *big.Int) *big.Int {
+	return new(big.Int).SetBytes(crypto.Keccak256(SlotBytes(slot)))
+}
+
+// Location is the place of a value of Size bytes in a storage slot, Offset bytes from the low-order end of the word.
+type Location struct {
+	Slot   *big.Int
+	Offset int
+	Size   int
+}
+
+// Packed returns the location of a value of size bytes which is packed into slot at offset bytes from the low-order end,
+// e.g. the second of two uint128 variables declared one after the other is Packed(slot, 16, 16).
+func Packed(slot *big.Int, offset, size int) Location {
+	return Location{Slot: slot, Offset: offset, Size: size}
+}
+
+// ArrayElement returns the location of element index of the dynamic array at slot with elements of elementSize bytes.
+// Elements of up to 16 bytes are packed, several into one slot; elements of more than 32 bytes (structs, static
+// arrays) occupy ceil(elementSize / 32) slots each, the location covers their first slot.
+// For fixed size arrays, use StaticArrayElement.
+func ArrayElement(slot *big.Int, index *big.Int, elementSize int) Location {
+	return StaticArrayElement(ArrayDataSlot(slot), index, elementSize)
+}
+
+// StaticArrayElement is like ArrayElement for a fixed size array whose first element is stored at slot.
+func StaticArrayElement(slot *big.Int, index *big.Int, elementSize int) Location {
+	if elementSize <= 0 {
+		elementSize = WordSize
+	}
+
+	location := Location{Size: elementSize}
+
+	if elementSize > WordSize/2 {
+		slots := int64((elementSize + WordSize - 1) / WordSize)
+		location.Slot = new(big.Int).Mul(index, big.NewInt(slots))
+		if elementSize > WordSize {
+			location.Size = WordSize
+		}
+	} else {
+		perSlot := big.NewInt(int64(WordSize / elementSize))
+		position := new(big.Int)
+		location.Slot, position = new(big.Int).DivMod(index, perSlot, position)
+		location.Offset = int(position.Int64()) * elementSize
+	}
+
+	location.Slot.Add(location.Slot, slot)
+	location.Slot.Mod(location.Slot, maxSlot)
+
+	return location
+}
+
+// Extract returns the bytes of the location from the word of its slot.
+func (location Location) Extract(word []byte) ([]byte, error) {
+	word = PadWord(word)
+
+	if location.Offset < 0 || location.Size <= 0 || location.Offset+location.Size > WordSize {
+		return nil, fmt.Errorf("invalid location of %v bytes at offset %v", location.Size, location.Offset)
+	}
+
+	return word[WordSize-location.Offset-location.Size : WordSize-location.Offset], nil
+}
+
+// Word returns the 32 byte word of a eth_getStorageAt result.
+func Word(value *rpctypes.HexString) []byte {
+	if value == nil {
+		return PadWord(nil)
+	}
+
+	return PadWord(value.Bytes())
+}
+
+// DecodeUint decodes an unsigned integer from a word or the bytes of a packed value.
+func DecodeUint(value []byte) *big.Int {
+	return new(big.Int).SetBytes(value)
+}
+
+// DecodeInt decodes a two's complement signed integer from a word or the bytes of a packed value.
+func DecodeInt(value []byte) *big.Int {
+	result := new(big.Int).SetBytes(value)
+
+	if len(value) > 0 && value[0]&0x80 != 0 {
+		result.Sub(result, new(big.Int).Lsh(big.NewInt(1), uint(8*len(value))))
+	}
+
+	return result
+}
+
+// DecodeBool decodes a bool from a word or the byte of a packed value.
+func DecodeBool(value []byte) (bool, error) {
+	b := DecodeUint(value)
+
+	switch {
+	case b.Sign() == 0:
+		return false, nil
+	case b.Cmp(big.NewInt(1)) == 0:
+		return true, nil
+	}
+
+	return false, fmt.Errorf("%x is no bool", value)
+}
+
+// DecodeAddress decodes an address from a word or the 20 bytes of a packed value.
+func DecodeAddress(value []byte) (*rpctypes.EtherAddress, error) {
+	if len(value) < 20 {
+		return nil, errors.New("address needs 20 bytes")
+	}
+
+	for _, b := range value[:len(value)-20] {
+		if b != 0 {
+			return nil, fmt.Errorf("%x is no address", value)
+		}
+	}
+
+	return new(rpctypes.EtherAddress).FromBytes(value[len(value)-20:])
+}
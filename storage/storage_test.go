@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/Leondroids/go-ethereum-rpc/rpctypes"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestEIP1967Slots(t *testing.T) {
+	tests := []struct {
+		slot     *big.Int
+		expected string
+	}{
+		{ImplementationSlot, "360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc"},
+		{AdminSlot, "b53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103"},
+		{BeaconSlot, "a3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50"},
+	}
+
+	for _, test := range tests {
+		if actual := fmt.Sprintf("%064x", test.slot); actual != test.expected {
+			t.Errorf("wrong eip-1967 slot [Expected: %v, Actual: %v]", test.expected, actual)
+		}
+	}
+}
+
+func TestArrayDataSlot(t *testing.T) {
+	expected := "290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563"
+
+	if actual := fmt.Sprintf("%064x", ArrayDataSlot(big.NewInt(0))); actual != expected {
+		t.Errorf("wrong data slot [Expected: %v, Actual: %v]", expected, actual)
+	}
+}
+
+func TestMappingSlot(t *testing.T) {
+	address, _ := new(rpctypes.EtherAddress).FromString("0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f")
+
+	// balances[address] with balances at slot 3
+	key := make([]byte, 64)
+	copy(key[12:32], address.Bytes())
+	key[63] = 3
+	expected := new(big.Int).SetBytes(crypto.Keccak256(key))
+
+	if actual := MappingSlot(big.NewInt(3), AddressKey(address)); actual.Cmp(expected) != 0 {
+		t.Errorf("wrong mapping slot [Expected: %x, Actual: %x]", expected, actual)
+	}
+
+	// allowances[address][address] is keccak(address . keccak(address . slot))
+	nested := MappingSlot(MappingSlot(big.NewInt(3), AddressKey(address)), AddressKey(address))
+	expected = new(big.Int).SetBytes(crypto.Keccak256(key[:32], expected.Bytes()))
+
+	if nested.Cmp(expected) != 0 {
+		t.Errorf("wrong nested mapping slot [Expected: %x, Actual: %x]", expected, nested)
+	}
+
+	// string keys are hashed unpadded
+	expected = new(big.Int).SetBytes(crypto.Keccak256([]byte("key"), SlotBytes(big.NewInt(1))))
+	if actual := MappingSlot(big.NewInt(1), StringKey("key")); actual.Cmp(expected) != 0 {
+		t.Errorf("wrong string mapping slot [Expected: %x, Actual: %x]", expected, actual)
+	}
+}
+
+func TestStaticArrayElement(t *testing.T) {
+	tests := []struct {
+		index       int64
+		elementSize int
+		slot        int64
+		offset      int
+		size        int
+	}{
+		{0, 32, 10, 0, 32},
+		{3, 32, 13, 0, 32},
+		{3, 64, 16, 0, 32},  // two slots per element
+		{5, 8, 11, 8, 8},    // uint64: 4 per slot
+		{1, 16, 10, 16, 16}, // uint128: 2 per slot
+		{2, 20, 12, 0, 20},  // address: 1 per slot
+		{31, 1, 10, 31, 1},  // uint8: 32 per slot
+		{32, 1, 11, 0, 1},
+	}
+
+	for _, test := range tests {
+		location := StaticArrayElement(big.NewInt(10), big.NewInt(test.index), test.elementSize)
+		if location.Slot.Int64() != test.slot || location.Offset != test.offset || location.Size != test.size {
+			t.Errorf("wrong location of element %v of %v bytes [Expected: %v, Actual: %v]", test.index, test.elementSize,
+				[]interface{}{test.slot, test.offset, test.size}, []interface{}{location.Slot, location.Offset, location.Size})
+		}
+	}
+
+	location := ArrayElement(big.NewInt(0), big.NewInt(1), 32)
+	if expected := new(big.Int).Add(ArrayDataSlot(big.NewInt(0)), big.NewInt(1)); location.Slot.Cmp(expected) != 0 {
+		t.Errorf("wrong dynamic array slot [Expected: %x, Actual: %x]", expected, location.Slot)
+	}
+}
+
+func TestLocation_Extract(t *testing.T) {
+	// slot holding: address owner (offset 0), bool paused (offset 20), uint16 fee (offset 21)
+	word, _ := rpctypes.NewHexString("0x000000000000000000012c019d8a62f656a8d1615c1294fd71e9cfb3e4855a4f")
+
+	owner, err := Packed(big.NewInt(0), 0, 20).Extract(Word(word))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	address, err := DecodeAddress(owner)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if address.String() != "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f" {
+		t.Errorf("wrong owner [Expected: %v, Actual: %v]", "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f", address.String())
+	}
+
+	paused, _ := Packed(big.NewInt(0), 20, 1).Extract(Word(word))
+	if value, err := DecodeBool(paused); err != nil || !value {
+		t.Errorf("wrong paused [Expected: %v, Actual: %v, %v]", true, value, err)
+	}
+
+	fee, _ := Packed(big.NewInt(0), 21, 2).Extract(Word(word))
+	if value := DecodeUint(fee); value.Int64() != 300 {
+		t.Errorf("wrong fee [Expected: %v, Actual: %v]", 300, value)
+	}
+
+	if _, err := Packed(big.NewInt(0), 31, 2).Extract(Word(word)); err == nil {
+		t.Errorf("location beyond the word extracted [Expected: %v, Actual: %v]", "error", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	if value := DecodeInt([]byte{0xff, 0xfe}); value.Int64() != -2 {
+		t.Errorf("wrong int16 [Expected: %v, Actual: %v]", -2, value)
+	}
+
+	if value := DecodeInt(PadWord([]byte{0x7f})); value.Int64() != 127 {
+		t.Errorf("wrong int256 [Expected: %v, Actual: %v]", 127, value)
+	}
+
+	if _, err := DecodeBool([]byte{0x02}); err == nil {
+		t.Errorf("invalid bool decoded [Expected: %v, Actual: %v]", "error", err)
+	}
+
+	if _, err := DecodeAddress(PadWord([]byte{0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0})); err == nil {
+		t.Errorf("word with dirty high bytes decoded as address [Expected: %v, Actual: %v]", "error", err)
+	}
+}